@@ -4,6 +4,7 @@ package main
 import (
 	"context"
 	"log"
+	"math"
 	"net/http"
 	"os"
 	"os/signal"
@@ -30,8 +31,18 @@ func main() {
 	log.Printf("  NUMA Enabled: %v", cfg.NUMAEnabled)
 	log.Printf("  XDP Enabled: %v", cfg.XDPEnabled)
 
-	// Set GOMAXPROCS based on available CPUs
+	// Set GOMAXPROCS based on available CPUs, clamped to the cgroup v2 CPU
+	// quota when running in a container that's been given fewer CPUs than
+	// the host has cores.
 	numCPU := runtime.NumCPU()
+	if limits, err := memory.DetectCgroupLimits(); err != nil {
+		log.Printf("Warning: failed to detect cgroup limits: %v", err)
+	} else if limits.Available && limits.CPUQuotaCPUs > 0 {
+		if quotaCPUs := int(math.Ceil(limits.CPUQuotaCPUs)); quotaCPUs < numCPU {
+			log.Printf("  Cgroup CPU quota (%.2f) is below host CPU count (%d), using %d", limits.CPUQuotaCPUs, numCPU, quotaCPUs)
+			numCPU = quotaCPUs
+		}
+	}
 	runtime.GOMAXPROCS(numCPU)
 	log.Printf("  GOMAXPROCS: %d", numCPU)
 
@@ -53,10 +64,10 @@ func main() {
 		log.Fatalf("Failed to create server: %v", err)
 	}
 
-	// Start server in a goroutine
+	// Start the HTTP API and gRPC control plane in a goroutine
 	go func() {
-		log.Printf("Server listening on %s:%d", cfg.ServerHost, cfg.ServerPort)
-		if err := srv.Start(); err != nil && err != http.ErrServerClosed {
+		log.Printf("Server listening on %s:%d (HTTP), %s:%d (gRPC)", cfg.ServerHost, cfg.ServerPort, cfg.ServerHost, cfg.GRPCPort)
+		if err := srv.StartAll(); err != nil && err != http.ErrServerClosed {
 			log.Fatalf("Server failed: %v", err)
 		}
 	}()
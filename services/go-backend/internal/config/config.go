@@ -29,10 +29,24 @@ type Config struct {
 	MemorySlotSize    int
 	MemoryPreallocate bool
 
+	// Batch UDP settings: an alternative to XDP for hosts without AF_XDP
+	// support, using the sendmmsg(2)/recvmmsg(2) fast path (see
+	// internal/xdp/batch.go). Requires MemoryPoolSize > 0 to back
+	// ReadBatch's packet buffers.
+	BatchEnabled    bool
+	BatchListenAddr string
+
 	// Metrics
 	MetricsEnabled bool
 	MetricsPort    int
 
+	// ResourceAccountingEnabled turns on per-request CPU/allocation
+	// accounting in the HTTP middleware. It's opt-in: it locks the
+	// handling goroutine to its OS thread for the request's duration to
+	// get accurate getrusage(RUSAGE_THREAD) samples, which costs some
+	// scheduling flexibility under high concurrency.
+	ResourceAccountingEnabled bool
+
 	// Timeouts
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
@@ -62,10 +76,16 @@ func Load() *Config {
 		MemorySlotSize:    getEnvInt("MEMORY_SLOT_SIZE", 2048),
 		MemoryPreallocate: getEnvBool("MEMORY_PREALLOCATE", true),
 
+		// Batch UDP
+		BatchEnabled:    getEnvBool("BATCH_ENABLED", false),
+		BatchListenAddr: getEnv("BATCH_LISTEN_ADDR", ":9099"),
+
 		// Metrics
 		MetricsEnabled: getEnvBool("METRICS_ENABLED", true),
 		MetricsPort:    getEnvInt("METRICS_PORT", 9090),
 
+		ResourceAccountingEnabled: getEnvBool("RESOURCE_ACCOUNTING_ENABLED", false),
+
 		// Timeouts
 		ReadTimeout:  getEnvDuration("READ_TIMEOUT", 30*time.Second),
 		WriteTimeout: getEnvDuration("WRITE_TIMEOUT", 30*time.Second),
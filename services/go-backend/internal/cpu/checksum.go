@@ -0,0 +1,69 @@
+package cpu
+
+import (
+	"encoding/binary"
+	"runtime"
+)
+
+// hasWideChecksum gates checksumFolded64, which folds 8 bytes per loop
+// iteration instead of 2. It is true on amd64 only; everywhere else falls
+// back to the portable 16-bit loop.
+var hasWideChecksum = runtime.GOARCH == "amd64"
+
+// Checksum computes the RFC 1071 Internet checksum (one's-complement sum
+// folded to 16 bits) used by IPv4, UDP and TCP headers. It's the same
+// algorithm internal/xdp's header Serialize methods need, exposed here so
+// the offload relay's userspace fallback path (internal/offload) and any
+// future caller share one runtime-dispatched implementation instead of
+// each rolling their own loop.
+func Checksum(data []byte) uint16 {
+	if hasWideChecksum {
+		return checksumFolded64(data)
+	}
+	return checksumScalar(data)
+}
+
+// checksumScalar is the portable 16-bit-at-a-time accumulator.
+func checksumScalar(data []byte) uint16 {
+	var sum uint32
+	n := len(data)
+
+	for i := 0; i+1 < n; i += 2 {
+		sum += uint32(binary.BigEndian.Uint16(data[i : i+2]))
+	}
+	if n%2 == 1 {
+		sum += uint32(data[n-1]) << 8
+	}
+
+	for sum > 0xFFFF {
+		sum = (sum & 0xFFFF) + (sum >> 16)
+	}
+	return ^uint16(sum)
+}
+
+// checksumFolded64 sums data 8 bytes at a time into a 64-bit accumulator
+// and folds carries once at the end, instead of after every 16-bit word.
+// It is plain Go: ordinary 32-bit loads added into a 64-bit integer, with
+// no SSE2 or AVX2 vector instructions. It is gated behind hasWideChecksum
+// rather than used unconditionally.
+func checksumFolded64(data []byte) uint16 {
+	var sum uint64
+	n := len(data)
+	i := 0
+
+	for ; i+8 <= n; i += 8 {
+		sum += uint64(binary.BigEndian.Uint32(data[i : i+4]))
+		sum += uint64(binary.BigEndian.Uint32(data[i+4 : i+8]))
+	}
+	for ; i+2 <= n; i += 2 {
+		sum += uint64(binary.BigEndian.Uint16(data[i : i+2]))
+	}
+	if i < n {
+		sum += uint64(data[n-1]) << 8
+	}
+
+	sum = (sum & 0xFFFFFFFF) + (sum >> 32)
+	sum = (sum & 0xFFFF) + (sum >> 16)
+	sum = (sum & 0xFFFF) + (sum >> 16)
+	return ^uint16(sum)
+}
@@ -0,0 +1,77 @@
+package cpu
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// TestChecksumScalarKnownValues checks checksumScalar against the RFC 1071
+// §3 worked example and a couple of edge cases (empty input, odd length).
+func TestChecksumScalarKnownValues(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want uint16
+	}{
+		{
+			name: "RFC 1071 example",
+			data: []byte{0x00, 0x01, 0xf2, 0x03, 0xf4, 0xf5, 0xf6, 0xf7},
+			want: 0x220d,
+		},
+		{
+			name: "empty",
+			data: nil,
+			want: 0xffff,
+		},
+		{
+			name: "single odd byte is padded with a zero low byte",
+			data: []byte{0xAB},
+			want: ^uint16(0xAB00),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := checksumScalar(tt.data); got != tt.want {
+				t.Errorf("checksumScalar(%x) = %#04x, want %#04x", tt.data, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestChecksumFoldedMatchesScalar verifies checksumFolded64 agrees with
+// checksumScalar across a range of lengths that cross its 8-byte and
+// 2-byte loop boundaries, since the two must be interchangeable for
+// Checksum's runtime dispatch (hasWideChecksum) to be correct.
+func TestChecksumFoldedMatchesScalar(t *testing.T) {
+	for n := 0; n <= 40; n++ {
+		data := make([]byte, n)
+		for i := range data {
+			data[i] = byte(i*31 + 7)
+		}
+
+		scalar := checksumScalar(data)
+		folded := checksumFolded64(data)
+		if scalar != folded {
+			t.Errorf("len=%d: checksumScalar=%#04x checksumFolded64=%#04x, want equal", n, scalar, folded)
+		}
+	}
+}
+
+// TestChecksumSelfVerifies checks the standard Internet-checksum
+// property: writing the computed checksum into its own field and
+// re-summing the buffer yields zero (folded to 16 bits).
+func TestChecksumSelfVerifies(t *testing.T) {
+	data := make([]byte, 20)
+	for i := range data {
+		data[i] = byte(i * 3)
+	}
+	data[10], data[11] = 0, 0 // zero the checksum field before computing
+
+	sum := Checksum(data)
+	binary.BigEndian.PutUint16(data[10:12], sum)
+
+	if got := Checksum(data); got != 0 {
+		t.Errorf("Checksum after writing back self-checksum = %#04x, want 0", got)
+	}
+}
@@ -0,0 +1,35 @@
+//go:build amd64
+
+package cpu
+
+import "unsafe"
+
+// nonTemporalThreshold approximates a per-core L2 size for the hosts this
+// service targets; frames at or above it are copied with non-temporal
+// stores so a single large UMEM frame doesn't evict the rest of L2/L3 for
+// the other RX queues sharing it.
+const nonTemporalThreshold = 256 * 1024
+
+//go:noescape
+func copyNT(dst, src unsafe.Pointer, n uintptr)
+
+// CopyFrame copies src into dst, truncating to len(dst) if src is longer,
+// and returns the number of bytes copied — the same contract as the
+// copy() builtin. At or above nonTemporalThreshold it switches to copyNT's
+// non-temporal stores (see copy_amd64.s); smaller frames, which are the
+// common case, stay on copy() since MOVNTI's write-combining only pays
+// off once the frame no longer fits comfortably in cache.
+func CopyFrame(dst, src []byte) int {
+	n := len(src)
+	if n > len(dst) {
+		n = len(dst)
+	}
+	if n == 0 {
+		return 0
+	}
+	if n >= nonTemporalThreshold {
+		copyNT(unsafe.Pointer(&dst[0]), unsafe.Pointer(&src[0]), uintptr(n))
+		return n
+	}
+	return copy(dst, src)
+}
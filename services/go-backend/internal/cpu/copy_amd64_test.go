@@ -0,0 +1,45 @@
+//go:build amd64
+
+package cpu
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestCopyFrameNonTemporal exercises CopyFrame's copyNT path (frames at or
+// above nonTemporalThreshold), which TestCopyFrame in copy_test.go never
+// reaches since its inputs are all small.
+func TestCopyFrameNonTemporal(t *testing.T) {
+	src := make([]byte, nonTemporalThreshold+64)
+	for i := range src {
+		src[i] = byte(i)
+	}
+	dst := make([]byte, len(src))
+
+	n := CopyFrame(dst, src)
+	if n != len(src) {
+		t.Fatalf("CopyFrame returned %d, want %d", n, len(src))
+	}
+	if !bytes.Equal(dst, src) {
+		t.Fatal("CopyFrame produced mismatched output on the non-temporal-store path")
+	}
+}
+
+// TestCopyFrameNonTemporalTruncates checks the truncate-to-dst contract
+// still holds when the copy is large enough to take the copyNT path.
+func TestCopyFrameNonTemporalTruncates(t *testing.T) {
+	src := make([]byte, nonTemporalThreshold+64)
+	for i := range src {
+		src[i] = byte(i)
+	}
+	dst := make([]byte, nonTemporalThreshold)
+
+	n := CopyFrame(dst, src)
+	if n != len(dst) {
+		t.Fatalf("CopyFrame returned %d, want %d", n, len(dst))
+	}
+	if !bytes.Equal(dst, src[:len(dst)]) {
+		t.Fatal("CopyFrame truncated output does not match src prefix")
+	}
+}
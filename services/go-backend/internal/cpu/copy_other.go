@@ -0,0 +1,11 @@
+//go:build !amd64
+
+package cpu
+
+// CopyFrame copies src into dst, truncating to len(dst) if src is
+// longer, and returns the number of bytes copied. Non-amd64 builds don't
+// get the non-temporal-store fast path (see copy_amd64.go/.s) — copy()
+// is already the best this platform offers.
+func CopyFrame(dst, src []byte) int {
+	return copy(dst, src)
+}
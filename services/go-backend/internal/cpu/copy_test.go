@@ -0,0 +1,44 @@
+package cpu
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestCopyFrame covers CopyFrame's copy()-builtin contract: copy everything
+// when dst is long enough, truncate to len(dst) otherwise, and report the
+// number of bytes actually copied. This exercises both the copy_amd64.go
+// and copy_other.go implementations identically since they share the
+// contract; the amd64-only non-temporal-store path is covered separately
+// in copy_amd64_test.go.
+func TestCopyFrame(t *testing.T) {
+	t.Run("copies all of src when dst is at least as long", func(t *testing.T) {
+		src := []byte("hello, xdp")
+		dst := make([]byte, len(src))
+		n := CopyFrame(dst, src)
+		if n != len(src) {
+			t.Fatalf("CopyFrame returned %d, want %d", n, len(src))
+		}
+		if !bytes.Equal(dst, src) {
+			t.Fatalf("CopyFrame(dst, %q) = %q", src, dst)
+		}
+	})
+
+	t.Run("truncates to len(dst) and returns the truncated count", func(t *testing.T) {
+		src := []byte("0123456789")
+		dst := make([]byte, 4)
+		n := CopyFrame(dst, src)
+		if n != 4 {
+			t.Fatalf("CopyFrame returned %d, want 4", n)
+		}
+		if !bytes.Equal(dst, src[:4]) {
+			t.Fatalf("CopyFrame(dst[:4], %q) = %q, want %q", src, dst, src[:4])
+		}
+	})
+
+	t.Run("empty src copies nothing", func(t *testing.T) {
+		if n := CopyFrame(make([]byte, 4), nil); n != 0 {
+			t.Fatalf("CopyFrame with empty src returned %d, want 0", n)
+		}
+	})
+}
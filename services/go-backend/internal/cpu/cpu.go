@@ -0,0 +1,35 @@
+// Package cpu detects CPU features relevant to this module's
+// packet-processing hot paths (internal/memory, internal/xdp,
+// internal/offload), mirroring the shape of Go's own internal/cpu
+// package: a set of booleans computed once at init time and consulted by
+// runtime-dispatched fast paths instead of re-probing CPUID on every call.
+package cpu
+
+import "golang.org/x/sys/cpu"
+
+// CacheLineSize is the cache line size assumed for alignment and
+// false-sharing avoidance across this module. 64 bytes covers every
+// mainstream x86-64 and ARM64 part this service targets.
+const CacheLineSize = 64
+
+var (
+	// HasSSE42 reports whether the CPU supports SSE4.2 (amd64).
+	HasSSE42 = cpu.X86.HasSSE42
+	// HasAVX2 reports whether the CPU supports AVX2 (amd64).
+	HasAVX2 = cpu.X86.HasAVX2
+	// HasBMI2 reports whether the CPU supports BMI2 (amd64).
+	HasBMI2 = cpu.X86.HasBMI2
+	// HasNEON reports whether the CPU supports NEON/ASIMD (arm, arm64).
+	HasNEON = cpu.ARM.HasNEON || cpu.ARM64.HasASIMD
+	// HasARMv8Crypto reports whether the CPU supports the ARMv8
+	// cryptographic extensions (AES + PMULL) on arm64.
+	HasARMv8Crypto = cpu.ARM64.HasAES && cpu.ARM64.HasPMULL
+)
+
+// CacheLinePad is zero-cost but for its size: embed it between fields
+// that are written by different goroutines so they don't end up sharing
+// a cache line, the same trick Go's own internal/cpu and sync/atomic
+// types use to avoid false sharing.
+type CacheLinePad struct {
+	_ [CacheLineSize]byte
+}
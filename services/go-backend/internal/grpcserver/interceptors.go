@@ -0,0 +1,44 @@
+package grpcserver
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"github.com/penguintechinc/project-template/services/go-backend/internal/metrics"
+)
+
+// UnaryMetricsInterceptor records RecordGRPCRequest for every unary call,
+// mirroring metricsMiddleware in internal/server/server.go for the HTTP
+// side.
+func UnaryMetricsInterceptor(m *metrics.Metrics) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+
+		m.GRPCActiveRequests.Inc()
+		defer m.GRPCActiveRequests.Dec()
+
+		resp, err := handler(ctx, req)
+
+		m.RecordGRPCRequest(info.FullMethod, status.Code(err).String(), time.Since(start).Seconds())
+		return resp, err
+	}
+}
+
+// StreamMetricsInterceptor records RecordGRPCRequest once a streaming call
+// (unary-equivalent: one observation per call, not per message) finishes.
+func StreamMetricsInterceptor(m *metrics.Metrics) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+
+		m.GRPCActiveRequests.Inc()
+		defer m.GRPCActiveRequests.Dec()
+
+		err := handler(srv, ss)
+
+		m.RecordGRPCRequest(info.FullMethod, status.Code(err).String(), time.Since(start).Seconds())
+		return err
+	}
+}
@@ -0,0 +1,79 @@
+package grpcserver
+
+import (
+	"fmt"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+
+	"github.com/penguintechinc/project-template/services/go-backend/internal/grpcserver/pb"
+	"github.com/penguintechinc/project-template/services/go-backend/internal/grpcsrv"
+	apiv1pb "github.com/penguintechinc/project-template/services/go-backend/internal/grpcsrv/pb"
+	"github.com/penguintechinc/project-template/services/go-backend/internal/memory"
+	"github.com/penguintechinc/project-template/services/go-backend/internal/metrics"
+)
+
+// Listener wraps the grpc.Server backing the ControlPlane and ApiV1
+// services plus the standard health and reflection services, ready to
+// Serve on a net.Listener.
+type Listener struct {
+	grpcServer *grpc.Server
+	healthSrv  *health.Server
+	controlSrv *Server
+	apiv1Srv   *grpcsrv.Server
+}
+
+// NewListener builds a Listener serving ControlPlane over memPool and
+// ApiV1 over apiv1Srv (see grpcsrv.NewServer) on the same *grpc.Server,
+// with unary/stream interceptors that feed m. apiv1Srv may be nil, in
+// which case only ControlPlane is registered.
+func NewListener(memPool memory.Pool, m *metrics.Metrics, apiv1Srv *grpcsrv.Server) *Listener {
+	grpcServer := grpc.NewServer(
+		grpc.UnaryInterceptor(UnaryMetricsInterceptor(m)),
+		grpc.StreamInterceptor(StreamMetricsInterceptor(m)),
+	)
+
+	controlSrv := NewServer(memPool)
+	pb.RegisterControlPlaneServer(grpcServer, controlSrv)
+
+	healthSrv := health.NewServer()
+	healthSrv.SetServingStatus("controlplane.v1.ControlPlane", healthpb.HealthCheckResponse_SERVING)
+
+	if apiv1Srv != nil {
+		apiv1pb.RegisterApiV1Server(grpcServer, apiv1Srv)
+		healthSrv.SetServingStatus("apiv1.v1.ApiV1", healthpb.HealthCheckResponse_SERVING)
+	}
+
+	healthpb.RegisterHealthServer(grpcServer, healthSrv)
+
+	reflection.Register(grpcServer)
+
+	return &Listener{grpcServer: grpcServer, healthSrv: healthSrv, controlSrv: controlSrv, apiv1Srv: apiv1Srv}
+}
+
+// Serve accepts connections on lis until the server is stopped.
+func (l *Listener) Serve(lis net.Listener) error {
+	return l.grpcServer.Serve(lis)
+}
+
+// ListenAndServe opens a TCP listener on addr and serves on it.
+func (l *Listener) ListenAndServe(addr string) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	return l.Serve(lis)
+}
+
+// GracefulStop marks the health service NOT_SERVING and stops the
+// gRPC server once in-flight RPCs finish.
+func (l *Listener) GracefulStop() {
+	l.healthSrv.SetServingStatus("controlplane.v1.ControlPlane", healthpb.HealthCheckResponse_NOT_SERVING)
+	if l.apiv1Srv != nil {
+		l.healthSrv.SetServingStatus("apiv1.v1.ApiV1", healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+	l.grpcServer.GracefulStop()
+}
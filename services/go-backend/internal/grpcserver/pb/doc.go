@@ -0,0 +1,16 @@
+// Package pb holds the generated protobuf/gRPC bindings for
+// proto/controlplane/v1/controlplane.proto: the message types and the
+// ControlPlane client/server interfaces internal/grpcserver implements.
+//
+// To regenerate after editing the .proto, with protoc and the Go plugins
+// on PATH:
+//
+//	protoc \
+//	  --go_out=. --go_opt=module=github.com/penguintechinc/project-template/services/go-backend \
+//	  --go-grpc_out=. --go-grpc_opt=module=github.com/penguintechinc/project-template/services/go-backend \
+//	  proto/controlplane/v1/controlplane.proto
+//
+// run from services/go-backend.
+package pb
+
+//go:generate protoc --go_out=.. --go_opt=module=github.com/penguintechinc/project-template/services/go-backend/internal/grpcserver --go-grpc_out=.. --go-grpc_opt=module=github.com/penguintechinc/project-template/services/go-backend/internal/grpcserver ../../../proto/controlplane/v1/controlplane.proto
@@ -0,0 +1,428 @@
+// Package grpcserver implements the ControlPlane gRPC service
+// (internal/grpcserver/pb) that manages the XDP program, its AF_XDP
+// sockets, the shared memory pool, and the UDP relay offload. It's a
+// second entry point into the same subsystems the Gin HTTP handlers in
+// internal/server expose, meant for a sidecar that wants to drive packet
+// I/O directly instead of going through JSON.
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/penguintechinc/project-template/services/go-backend/internal/grpcserver/pb"
+	"github.com/penguintechinc/project-template/services/go-backend/internal/memory"
+	"github.com/penguintechinc/project-template/services/go-backend/internal/offload"
+	"github.com/penguintechinc/project-template/services/go-backend/internal/xdp"
+)
+
+// defaultStatsInterval is how often LoadXDPProgram streams stats back when
+// the request doesn't set stats_interval_seconds.
+const defaultStatsInterval = time.Second
+
+// socketEntry pairs an opened AF_XDP socket with the queue it was opened
+// for, so CloseXDPSocket can report it back.
+type socketEntry struct {
+	sock    *xdp.XDPSocket
+	queueID int
+}
+
+// Server implements pb.ControlPlaneServer.
+type Server struct {
+	pb.UnimplementedControlPlaneServer
+
+	memPool memory.Pool
+
+	mu           sync.Mutex
+	xdpProgram   *xdp.XDPProgram
+	ifaceName    string
+	relay        *offload.Relay
+	sockets      map[string]*socketEntry
+	nextSocketID uint64
+	socketPool   *xdp.SocketPool
+	bufPool      *memory.BufferPool
+}
+
+// NewServer creates a Server that hands out slots from memPool. The XDP
+// program itself isn't loaded until a LoadXDPProgram call arrives.
+func NewServer(memPool memory.Pool) *Server {
+	return &Server{
+		memPool: memPool,
+		sockets: make(map[string]*socketEntry),
+	}
+}
+
+// LoadXDPProgram attaches the dispatcher to req.InterfaceName and streams
+// back interface counters every stats_interval_seconds until the RPC's
+// context is cancelled or DetachXDPProgram tears the program down.
+func (s *Server) LoadXDPProgram(req *pb.LoadXDPProgramRequest, stream pb.ControlPlane_LoadXDPProgramServer) error {
+	s.mu.Lock()
+	if s.xdpProgram != nil {
+		s.mu.Unlock()
+		return status.Errorf(codes.FailedPrecondition, "XDP program already loaded on %s", s.ifaceName)
+	}
+
+	program, err := xdp.LoadXDPProgram(xdp.XDPConfig{
+		InterfaceName: req.GetInterfaceName(),
+		Mode:          xdp.ParseXDPMode(req.GetMode()),
+	})
+	if err != nil {
+		s.mu.Unlock()
+		return status.Errorf(codes.Internal, "failed to load XDP program: %v", err)
+	}
+
+	s.xdpProgram = program
+	s.ifaceName = req.GetInterfaceName()
+	s.relay = offload.NewRelay(program.RelayAllocations(), program.RelayCounters())
+	s.mu.Unlock()
+
+	interval := defaultStatsInterval
+	if req.GetStatsIntervalSeconds() > 0 {
+		interval = time.Duration(req.GetStatsIntervalSeconds()) * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		stats, err := xdp.GetInterfaceStats(req.GetInterfaceName())
+		if err == nil {
+			if sendErr := stream.Send(&pb.XDPProgramStats{
+				InterfaceName: req.GetInterfaceName(),
+				RxPackets:     stats.RxPackets,
+				RxBytes:       stats.RxBytes,
+				TxPackets:     stats.TxPackets,
+				TxBytes:       stats.TxBytes,
+				Drops:         stats.Drops,
+				Errors:        stats.Errors,
+			}); sendErr != nil {
+				return sendErr
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+
+		s.mu.Lock()
+		loaded := s.xdpProgram == program
+		s.mu.Unlock()
+		if !loaded {
+			return nil
+		}
+	}
+}
+
+// DetachXDPProgram detaches the loaded program, ending its LoadXDPProgram
+// stream and invalidating the relay and any open sockets/socket pool.
+func (s *Server) DetachXDPProgram(ctx context.Context, req *pb.DetachXDPProgramRequest) (*pb.DetachXDPProgramResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.xdpProgram == nil {
+		return nil, status.Error(codes.FailedPrecondition, "no XDP program is loaded")
+	}
+
+	if s.socketPool != nil {
+		if err := s.socketPool.Close(); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to close socket pool: %v", err)
+		}
+		s.socketPool = nil
+	}
+	for id, entry := range s.sockets {
+		entry.sock.Close()
+		delete(s.sockets, id)
+	}
+
+	if err := s.xdpProgram.Detach(); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to detach XDP program: %v", err)
+	}
+
+	ifaceName := s.ifaceName
+	s.xdpProgram = nil
+	s.relay = nil
+	s.ifaceName = ""
+
+	return &pb.DetachXDPProgramResponse{InterfaceName: ifaceName}, nil
+}
+
+// CreateXDPSocket opens an AF_XDP socket on one RX queue of the loaded
+// interface for out-of-process packet injection.
+func (s *Server) CreateXDPSocket(ctx context.Context, req *pb.CreateXDPSocketRequest) (*pb.CreateXDPSocketResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.xdpProgram == nil {
+		return nil, status.Error(codes.FailedPrecondition, "no XDP program is loaded")
+	}
+
+	cfg := xdp.DefaultSocketConfig(s.ifaceName)
+	cfg.QueueID = int(req.GetQueueId())
+	if req.GetNumFrames() > 0 {
+		cfg.NumFrames = int(req.GetNumFrames())
+	}
+	if req.GetFrameSize() > 0 {
+		cfg.FrameSize = int(req.GetFrameSize())
+	}
+	cfg.ZeroCopy = req.GetZeroCopy()
+
+	sock, err := xdp.NewXDPSocket(cfg)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create AF_XDP socket: %v", err)
+	}
+
+	id := fmt.Sprintf("sock-%d", atomic.AddUint64(&s.nextSocketID, 1))
+	s.sockets[id] = &socketEntry{sock: sock, queueID: int(req.GetQueueId())}
+
+	return &pb.CreateXDPSocketResponse{
+		SocketId:       id,
+		QueueId:        req.GetQueueId(),
+		FileDescriptor: int32(sock.FileDescriptor()),
+	}, nil
+}
+
+// CloseXDPSocket closes a socket previously opened with CreateXDPSocket.
+func (s *Server) CloseXDPSocket(ctx context.Context, req *pb.CloseXDPSocketRequest) (*pb.CloseXDPSocketResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.sockets[req.GetSocketId()]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "socket %q not found", req.GetSocketId())
+	}
+
+	if err := entry.sock.Close(); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to close socket: %v", err)
+	}
+	delete(s.sockets, req.GetSocketId())
+
+	return &pb.CloseXDPSocketResponse{SocketId: req.GetSocketId()}, nil
+}
+
+// AcquireBuffer reserves a slot in the shared memory pool.
+func (s *Server) AcquireBuffer(ctx context.Context, req *pb.AcquireBufferRequest) (*pb.AcquireBufferResponse, error) {
+	if s.memPool == nil {
+		return nil, status.Error(codes.FailedPrecondition, "memory pool not initialized")
+	}
+
+	idx, data, err := s.memPool.Acquire()
+	if err != nil {
+		return nil, status.Errorf(codes.ResourceExhausted, "memory pool exhausted: %v", err)
+	}
+
+	return &pb.AcquireBufferResponse{SlotIndex: int32(idx), Capacity: int32(len(data))}, nil
+}
+
+// ReleaseBuffer returns a slot acquired with AcquireBuffer to the pool.
+func (s *Server) ReleaseBuffer(ctx context.Context, req *pb.ReleaseBufferRequest) (*pb.ReleaseBufferResponse, error) {
+	if s.memPool == nil {
+		return nil, status.Error(codes.FailedPrecondition, "memory pool not initialized")
+	}
+
+	if err := s.memPool.Release(int(req.GetSlotIndex())); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to release slot %d: %v", req.GetSlotIndex(), err)
+	}
+
+	return &pb.ReleaseBufferResponse{SlotIndex: req.GetSlotIndex()}, nil
+}
+
+// AddRelayAllocation registers a UDP relay allocation.
+func (s *Server) AddRelayAllocation(ctx context.Context, req *pb.AddRelayAllocationRequest) (*pb.RelayAllocation, error) {
+	relay, err := s.requireRelay()
+	if err != nil {
+		return nil, err
+	}
+
+	listen, peer, err := parseAllocationAddrs(req.GetListenIp(), int(req.GetListenPort()), req.GetPeerIp(), int(req.GetPeerPort()))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := relay.AddAllocation(req.GetId(), listen, peer); err != nil {
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	}
+
+	return &pb.RelayAllocation{Id: req.GetId(), ListenAddr: listen.String(), PeerAddr: peer.String()}, nil
+}
+
+// RemoveRelayAllocation tears down a previously registered allocation.
+func (s *Server) RemoveRelayAllocation(ctx context.Context, req *pb.RemoveRelayAllocationRequest) (*pb.RemoveRelayAllocationResponse, error) {
+	relay, err := s.requireRelay()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := relay.RemoveAllocation(req.GetId()); err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	return &pb.RemoveRelayAllocationResponse{Id: req.GetId()}, nil
+}
+
+// GetRelayAllocation returns one allocation and its packet/byte counters.
+func (s *Server) GetRelayAllocation(ctx context.Context, req *pb.GetRelayAllocationRequest) (*pb.RelayAllocation, error) {
+	relay, err := s.requireRelay()
+	if err != nil {
+		return nil, err
+	}
+
+	alloc, ok := relay.Allocation(req.GetId())
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "%v: id %d", offload.ErrAllocationNotFound, req.GetId())
+	}
+
+	stats, err := relay.Stats(req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return allocationToPB(alloc, stats), nil
+}
+
+// ListRelayAllocations returns every currently registered allocation.
+func (s *Server) ListRelayAllocations(ctx context.Context, req *pb.ListRelayAllocationsRequest) (*pb.ListRelayAllocationsResponse, error) {
+	relay, err := s.requireRelay()
+	if err != nil {
+		return nil, err
+	}
+
+	allocs := relay.Allocations()
+	resp := &pb.ListRelayAllocationsResponse{Allocations: make([]*pb.RelayAllocation, 0, len(allocs))}
+	for _, alloc := range allocs {
+		stats, err := relay.Stats(alloc.ID)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		resp.Allocations = append(resp.Allocations, allocationToPB(alloc, stats))
+	}
+	return resp, nil
+}
+
+// StreamPackets mirrors packets received on one RX queue of the loaded
+// XDP program to the caller: it reads req.QueueId off the stream's first
+// message, then forwards every buffer that queue's worker produces until
+// the client closes the stream.
+func (s *Server) StreamPackets(stream pb.ControlPlane_StreamPacketsServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	queues, bufPool, err := s.ensureSocketPool()
+	if err != nil {
+		return err
+	}
+
+	queueID := int(req.GetQueueId())
+	if queueID < 0 || queueID >= len(queues) {
+		return status.Errorf(codes.InvalidArgument, "queue %d out of range (0-%d)", queueID, len(queues)-1)
+	}
+	queue := queues[queueID]
+
+	// Drain any further client messages on a goroutine so the stream
+	// unblocks promptly once the caller closes its send side; selections
+	// after the first are currently ignored (see the request's doc
+	// comment in controlplane.proto).
+	go func() {
+		for {
+			if _, err := stream.Recv(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case buf, ok := <-queue:
+			if !ok {
+				return nil
+			}
+			frame := &pb.PacketFrame{
+				QueueId:           int32(queueID),
+				Data:              append([]byte(nil), buf.Data()...),
+				TimestampUnixNano: time.Now().UnixNano(),
+			}
+			bufPool.Put(buf)
+			if err := stream.Send(frame); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
+
+// ensureSocketPool lazily opens a SocketPool across the loaded interface's
+// RX queues the first time StreamPackets is called, so a control plane
+// that never streams packets never pays for it.
+func (s *Server) ensureSocketPool() ([]<-chan *memory.Buffer, *memory.BufferPool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.xdpProgram == nil {
+		return nil, nil, status.Error(codes.FailedPrecondition, "no XDP program is loaded")
+	}
+	if s.socketPool != nil {
+		return s.socketPool.Queues(), s.bufPool, nil
+	}
+	if s.memPool == nil {
+		return nil, nil, status.Error(codes.FailedPrecondition, "memory pool not initialized")
+	}
+
+	bufPool := memory.NewBufferPool(s.memPool)
+	pool, err := xdp.NewSocketPool(s.xdpProgram, xdp.DefaultSocketConfig(s.ifaceName), xdp.FanoutPerQueue, 0, bufPool)
+	if err != nil {
+		return nil, nil, status.Errorf(codes.Internal, "failed to open socket pool: %v", err)
+	}
+
+	s.socketPool = pool
+	s.bufPool = bufPool
+	return pool.Queues(), bufPool, nil
+}
+
+// requireRelay returns the active relay or a FailedPrecondition status if
+// no XDP program is loaded yet.
+func (s *Server) requireRelay() (*offload.Relay, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.relay == nil {
+		return nil, status.Error(codes.FailedPrecondition, "no XDP program is loaded")
+	}
+	return s.relay, nil
+}
+
+// allocationToPB converts an offload.Allocation/Stats pair to its wire
+// representation.
+func allocationToPB(alloc *offload.Allocation, stats offload.Stats) *pb.RelayAllocation {
+	return &pb.RelayAllocation{
+		Id:         alloc.ID,
+		ListenAddr: alloc.Listen.String(),
+		PeerAddr:   alloc.Peer.String(),
+		PacketsIn:  stats.PacketsIn,
+		BytesIn:    stats.BytesIn,
+		PacketsOut: stats.PacketsOut,
+		BytesOut:   stats.BytesOut,
+	}
+}
+
+// parseAllocationAddrs validates and builds the listen/peer UDP addresses
+// for AddRelayAllocation, mirroring the Gin handler's equivalent check in
+// internal/server/handlers.go.
+func parseAllocationAddrs(listenIP string, listenPort int, peerIP string, peerPort int) (*net.UDPAddr, *net.UDPAddr, error) {
+	listen := net.ParseIP(listenIP)
+	peer := net.ParseIP(peerIP)
+	if listen == nil || peer == nil {
+		return nil, nil, errors.New("invalid listen_ip or peer_ip")
+	}
+	return &net.UDPAddr{IP: listen, Port: listenPort}, &net.UDPAddr{IP: peer, Port: peerPort}, nil
+}
@@ -0,0 +1,86 @@
+package grpcsrv
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/penguintechinc/project-template/services/go-backend/internal/grpcsrv/pb"
+)
+
+// NewGatewayRoutes registers the ApiV1 RPCs on group under /grpc/v1,
+// forwarding each request straight to srv in-process. It's a hand-rolled
+// stand-in for a protoc-gen-grpc-gateway reverse proxy: the real
+// generator produces a ServeMux that dials back into the gRPC server over
+// a client connection, which is unnecessary complexity here since the
+// gateway and the gRPC server share the same process and srv is just a
+// Go value. Swap this for generated gateway code if ApiV1 ever needs to
+// front a gRPC server running elsewhere.
+func NewGatewayRoutes(group *gin.RouterGroup, srv pb.ApiV1Server) {
+	group.GET("/status", gatewayHandler(func(c *gin.Context) (interface{}, error) {
+		return srv.Status(c.Request.Context(), &pb.StatusRequest{})
+	}))
+	group.GET("/hello", gatewayHandler(func(c *gin.Context) (interface{}, error) {
+		return srv.Hello(c.Request.Context(), &pb.HelloRequest{})
+	}))
+	group.POST("/packet/forward", gatewayHandler(func(c *gin.Context) (interface{}, error) {
+		var req pb.PacketForwardRequest
+		if c.Request.ContentLength != 0 {
+			if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+				return nil, err
+			}
+		}
+		return srv.PacketForward(c.Request.Context(), &req)
+	}))
+	group.GET("/memory/stats", gatewayHandler(func(c *gin.Context) (interface{}, error) {
+		return srv.MemoryPoolStats(c.Request.Context(), &pb.MemoryPoolStatsRequest{})
+	}))
+	group.GET("/numa/info", gatewayHandler(func(c *gin.Context) (interface{}, error) {
+		return srv.NUMAInfo(c.Request.Context(), &pb.NUMAInfoRequest{})
+	}))
+}
+
+// gatewayHandler adapts an ApiV1 RPC call into a gin.HandlerFunc,
+// translating a gRPC status error (if any) into an HTTP status with
+// httpStatusFromGRPCError.
+func gatewayHandler(call func(c *gin.Context) (interface{}, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		resp, err := call(c)
+		if err != nil {
+			c.JSON(httpStatusFromGRPCError(err), gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// httpStatusFromGRPCError maps a gRPC status code to an HTTP status. The
+// listed codes follow grpc-gateway's default mapping except
+// FailedPrecondition, which maps to 503 (the status the Gin handlers
+// return for an uninitialized memory pool) rather than 400; every code
+// not listed maps to 500.
+func httpStatusFromGRPCError(err error) int {
+	switch status.Code(err) {
+	case codes.OK:
+		return http.StatusOK
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.FailedPrecondition:
+		return http.StatusServiceUnavailable
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,16 @@
+// Package pb holds the generated protobuf/gRPC bindings for
+// proto/apiv1/v1/apiv1.proto: the message types and the ApiV1
+// client/server interfaces internal/grpcsrv implements.
+//
+// To regenerate after editing the .proto, with protoc and the Go plugins
+// on PATH:
+//
+//	protoc \
+//	  --go_out=. --go_opt=module=github.com/penguintechinc/project-template/services/go-backend \
+//	  --go-grpc_out=. --go-grpc_opt=module=github.com/penguintechinc/project-template/services/go-backend \
+//	  proto/apiv1/v1/apiv1.proto
+//
+// run from services/go-backend.
+package pb
+
+//go:generate protoc --go_out=.. --go_opt=module=github.com/penguintechinc/project-template/services/go-backend/internal/grpcsrv --go-grpc_out=.. --go-grpc_opt=module=github.com/penguintechinc/project-template/services/go-backend/internal/grpcsrv ../../../proto/apiv1/v1/apiv1.proto
@@ -0,0 +1,195 @@
+// Package grpcsrv implements the ApiV1 gRPC service (internal/grpcsrv/pb),
+// a gRPC mirror of the read-mostly Gin routes in
+// internal/server/handlers.go (Status, Hello, PacketForward,
+// MemoryPoolStats, NUMAInfo). Unlike internal/grpcserver's ControlPlane
+// service, it doesn't own the XDP program/socket/relay lifecycle - it
+// just reads the same memory pool and AF_XDP socket the Gin handlers do.
+package grpcsrv
+
+import (
+	"context"
+	"runtime"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/penguintechinc/project-template/services/go-backend/internal/grpcsrv/pb"
+	"github.com/penguintechinc/project-template/services/go-backend/internal/memory"
+	"github.com/penguintechinc/project-template/services/go-backend/internal/xdp"
+)
+
+// Server implements pb.ApiV1Server.
+type Server struct {
+	pb.UnimplementedApiV1Server
+
+	startTime  time.Time
+	version    string
+	memoryPool memory.Pool
+	xdpMode    string
+	xdpIface   string
+	xdpSocket  *xdp.XDPSocket
+}
+
+// NewServer creates a Server that reads memPool and, if non-nil, xdpSocket.
+// xdpMode/xdpIface are reported verbatim in Status, the same as
+// server.Handlers reports cfg.XDPMode/cfg.XDPInterface.
+func NewServer(version string, memPool memory.Pool, xdpMode, xdpIface string, xdpSocket *xdp.XDPSocket) *Server {
+	return &Server{
+		startTime:  time.Now(),
+		version:    version,
+		memoryPool: memPool,
+		xdpMode:    xdpMode,
+		xdpIface:   xdpIface,
+		xdpSocket:  xdpSocket,
+	}
+}
+
+// Status mirrors GET /api/v1/status.
+func (s *Server) Status(ctx context.Context, req *pb.StatusRequest) (*pb.StatusResponse, error) {
+	numaInfo := memory.GetNUMAInfo()
+
+	resp := &pb.StatusResponse{
+		Status:       "running",
+		Service:      "go-backend",
+		Version:      s.version,
+		Timestamp:    time.Now().UTC().Format(time.RFC3339),
+		Uptime:       time.Since(s.startTime).String(),
+		GoVersion:    runtime.Version(),
+		NumCpu:       int32(runtime.NumCPU()),
+		NumGoroutine: int32(runtime.NumGoroutine()),
+		Numa: &pb.NUMAStatus{
+			Available:   numaInfo.Available,
+			NodeCount:   int32(numaInfo.NodeCount),
+			CurrentNode: int32(numaInfo.CurrentNode),
+			MemoryMb:    memoryMBToPB(numaInfo.MemoryMB),
+		},
+		Xdp: &pb.XDPStatus{
+			Supported:     xdp.IsXDPSupported(),
+			Mode:          s.xdpMode,
+			InterfaceName: s.xdpIface,
+		},
+	}
+
+	if s.xdpSocket != nil {
+		depths := s.xdpSocket.RingDepths()
+		resp.Xdp.RxRingDepth = depths.RxRingDepth
+		resp.Xdp.TxRingDepth = depths.TxRingDepth
+		resp.Xdp.FillRingDepth = depths.FillRingDepth
+		resp.Xdp.CompRingDepth = depths.CompRingDepth
+		resp.Xdp.RingDepthsSet = true
+	}
+
+	if s.memoryPool != nil {
+		stats := s.memoryPool.AggregateStats()
+		resp.MemoryPool = &pb.MemoryPoolStatus{
+			TotalSlots:       int32(stats.TotalSlots),
+			UsedSlots:        int32(stats.UsedSlots),
+			FreeSlots:        int32(stats.FreeSlots),
+			SlotSize:         int32(stats.SlotSize),
+			TotalMemoryBytes: int32(stats.TotalMemory),
+			PeakUsage:        stats.PeakUsage,
+		}
+	}
+
+	return resp, nil
+}
+
+// Hello mirrors GET /api/v1/hello.
+func (s *Server) Hello(ctx context.Context, req *pb.HelloRequest) (*pb.HelloResponse, error) {
+	return &pb.HelloResponse{
+		Message:   "Hello from Go high-performance backend!",
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Service:   "go-backend",
+	}, nil
+}
+
+// PacketForward mirrors POST /api/v1/packet/forward.
+func (s *Server) PacketForward(ctx context.Context, req *pb.PacketForwardRequest) (*pb.PacketForwardResponse, error) {
+	if s.memoryPool == nil {
+		return nil, status.Error(codes.FailedPrecondition, "memory pool not initialized")
+	}
+
+	slotIdx, buffer, err := s.memoryPool.Acquire()
+	if err != nil {
+		return nil, status.Errorf(codes.ResourceExhausted, "memory pool exhausted: %v", err)
+	}
+
+	frame := req.GetFrame()
+	if frame == nil {
+		frame = buffer
+	}
+
+	var transmitErr error
+	if s.xdpSocket != nil {
+		transmitErr = s.xdpSocket.Transmit([][]byte{frame})
+	}
+
+	if err := s.memoryPool.Release(slotIdx); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to release buffer: %v", err)
+	}
+
+	if transmitErr != nil {
+		return nil, status.Errorf(codes.Unavailable, "failed to transmit frame: %v", transmitErr)
+	}
+
+	return &pb.PacketForwardResponse{
+		Message:     "Packet processed successfully",
+		SlotUsed:    int32(slotIdx),
+		Transmitted: s.xdpSocket != nil,
+		Timestamp:   time.Now().UTC().Format(time.RFC3339),
+	}, nil
+}
+
+// MemoryPoolStats mirrors GET /api/v1/memory/stats.
+func (s *Server) MemoryPoolStats(ctx context.Context, req *pb.MemoryPoolStatsRequest) (*pb.MemoryPoolStatsResponse, error) {
+	if s.memoryPool == nil {
+		return nil, status.Error(codes.FailedPrecondition, "memory pool not initialized")
+	}
+
+	stats := s.memoryPool.AggregateStats()
+
+	return &pb.MemoryPoolStatsResponse{
+		TotalSlots:  int32(stats.TotalSlots),
+		UsedSlots:   int32(stats.UsedSlots),
+		FreeSlots:   int32(stats.FreeSlots),
+		SlotSize:    int32(stats.SlotSize),
+		TotalMemory: int32(stats.TotalMemory),
+		TotalAllocs: uint64(stats.TotalAllocs),
+		TotalFrees:  uint64(stats.TotalFrees),
+		PeakUsage:   stats.PeakUsage,
+		Utilization: float64(stats.UsedSlots) / float64(stats.TotalSlots) * 100,
+	}, nil
+}
+
+// NUMAInfo mirrors GET /api/v1/numa/info.
+func (s *Server) NUMAInfo(ctx context.Context, req *pb.NUMAInfoRequest) (*pb.NUMAInfoResponse, error) {
+	info := memory.GetNUMAInfo()
+
+	cpusPerNode := make(map[int32]*pb.CPUList, len(info.CPUsPerNode))
+	for node, cpus := range info.CPUsPerNode {
+		pbCpus := make([]int32, len(cpus))
+		for i, cpu := range cpus {
+			pbCpus[i] = int32(cpu)
+		}
+		cpusPerNode[int32(node)] = &pb.CPUList{Cpus: pbCpus}
+	}
+
+	return &pb.NUMAInfoResponse{
+		Available:   info.Available,
+		NodeCount:   int32(info.NodeCount),
+		CurrentNode: int32(info.CurrentNode),
+		CpusPerNode: cpusPerNode,
+		MemoryMb:    memoryMBToPB(info.MemoryMB),
+	}, nil
+}
+
+// memoryMBToPB converts the int-keyed map memory.GetNUMAInfo returns to the
+// int32 keys proto3 maps require.
+func memoryMBToPB(memoryMB map[int]int64) map[int32]int64 {
+	out := make(map[int32]int64, len(memoryMB))
+	for node, mb := range memoryMB {
+		out[int32(node)] = mb
+	}
+	return out
+}
@@ -4,11 +4,13 @@ package memory
 import (
 	"encoding/binary"
 	"sync"
+
+	"github.com/penguintechinc/project-template/services/go-backend/internal/cpu"
 )
 
 // Buffer provides a wrapper around a memory pool slot with helper methods.
 type Buffer struct {
-	pool    *MemoryPool
+	pool    Pool
 	slotIdx int
 	data    []byte
 	length  int // Actual data length (may be less than slot size)
@@ -16,12 +18,12 @@ type Buffer struct {
 
 // BufferPool manages a set of reusable buffers.
 type BufferPool struct {
-	memPool *MemoryPool
+	memPool Pool
 	buffers sync.Pool
 }
 
 // NewBufferPool creates a buffer pool backed by a memory pool.
-func NewBufferPool(memPool *MemoryPool) *BufferPool {
+func NewBufferPool(memPool Pool) *BufferPool {
 	return &BufferPool{
 		memPool: memPool,
 		buffers: sync.Pool{
@@ -108,7 +110,7 @@ func (b *Buffer) Write(p []byte) (n int, err error) {
 		p = p[:available]
 	}
 
-	copy(b.data[b.length:], p)
+	cpu.CopyFrame(b.data[b.length:], p)
 	b.length += len(p)
 	return len(p), nil
 }
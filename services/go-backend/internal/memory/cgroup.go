@@ -0,0 +1,158 @@
+// Package memory provides cgroup v2 aware pool sizing.
+package memory
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// cgroupV2Root is the standard mount point for the unified cgroup v2
+// hierarchy.
+const cgroupV2Root = "/sys/fs/cgroup"
+
+// CgroupLimits holds the resource limits and PSI pressure readings for the
+// cgroup v2 hierarchy the current process belongs to.
+type CgroupLimits struct {
+	Available bool
+
+	MemoryMaxBytes     int64 // -1 if unlimited ("max")
+	MemoryHighBytes    int64 // -1 if unlimited ("max")
+	MemoryCurrentBytes int64
+
+	CPUQuotaCPUs float64 // effective CPU quota as fractional CPUs, -1 if unlimited
+
+	PressureMemorySomeAvg10 float64
+	PressureCPUSomeAvg10    float64
+	PressureIOSomeAvg10     float64
+}
+
+// DetectCgroupLimits reads the cgroup v2 controller files for the current
+// process and returns the effective memory/CPU limits and PSI pressure.
+// Returns a zero-value CgroupLimits with Available=false (not an error) on
+// hosts without cgroup v2, e.g. most developer laptops.
+func DetectCgroupLimits() (CgroupLimits, error) {
+	if _, err := os.Stat(filepath.Join(cgroupV2Root, "cgroup.controllers")); err != nil {
+		return CgroupLimits{}, nil
+	}
+
+	limits := CgroupLimits{Available: true}
+
+	if v, err := readCgroupMaxValue("memory.max"); err == nil {
+		limits.MemoryMaxBytes = v
+	} else {
+		limits.MemoryMaxBytes = -1
+	}
+
+	if v, err := readCgroupMaxValue("memory.high"); err == nil {
+		limits.MemoryHighBytes = v
+	} else {
+		limits.MemoryHighBytes = -1
+	}
+
+	if v, err := readCgroupInt("memory.current"); err == nil {
+		limits.MemoryCurrentBytes = v
+	}
+
+	if quota, err := readCPUQuota(); err == nil {
+		limits.CPUQuotaCPUs = quota
+	} else {
+		limits.CPUQuotaCPUs = -1
+	}
+
+	limits.PressureMemorySomeAvg10 = readPressureSomeAvg10("memory.pressure")
+	limits.PressureCPUSomeAvg10 = readPressureSomeAvg10("cpu.pressure")
+	limits.PressureIOSomeAvg10 = readPressureSomeAvg10("io.pressure")
+
+	return limits, nil
+}
+
+// readCgroupMaxValue reads a cgroup v2 file whose content is either an
+// integer or the literal "max". It returns an error for "max" so callers
+// can distinguish "unlimited" from "read failed".
+func readCgroupMaxValue(name string) (int64, error) {
+	raw, err := readCgroupFile(name)
+	if err != nil {
+		return 0, err
+	}
+	if raw == "max" {
+		return 0, fmt.Errorf("%s is unlimited", name)
+	}
+	return strconv.ParseInt(raw, 10, 64)
+}
+
+// readCgroupInt reads a cgroup v2 file containing a single integer.
+func readCgroupInt(name string) (int64, error) {
+	raw, err := readCgroupFile(name)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(raw, 10, 64)
+}
+
+// readCPUQuota reads cpu.max ("$QUOTA $PERIOD" in microseconds, or
+// "max $PERIOD") and returns the effective CPU quota as fractional CPUs.
+func readCPUQuota() (float64, error) {
+	raw, err := readCgroupFile("cpu.max")
+	if err != nil {
+		return 0, err
+	}
+
+	fields := strings.Fields(raw)
+	if len(fields) != 2 {
+		return 0, fmt.Errorf("unexpected cpu.max format: %q", raw)
+	}
+
+	if fields[0] == "max" {
+		return 0, fmt.Errorf("cpu.max is unlimited")
+	}
+
+	quota, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, err
+	}
+	period, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil || period == 0 {
+		return 0, fmt.Errorf("invalid cpu.max period: %q", raw)
+	}
+
+	return quota / period, nil
+}
+
+// readPressureSomeAvg10 reads the "some avg10" field from a PSI pressure
+// file (memory.pressure, cpu.pressure, io.pressure). Returns 0 if the file
+// is missing or malformed, since pressure is advisory and shouldn't block
+// startup.
+func readPressureSomeAvg10(name string) float64 {
+	raw, err := readCgroupFile(name)
+	if err != nil {
+		return 0
+	}
+
+	for _, line := range strings.Split(raw, "\n") {
+		if !strings.HasPrefix(line, "some ") {
+			continue
+		}
+		for _, field := range strings.Fields(line) {
+			value, ok := strings.CutPrefix(field, "avg10=")
+			if !ok {
+				continue
+			}
+			if avg10, err := strconv.ParseFloat(value, 64); err == nil {
+				return avg10
+			}
+		}
+	}
+	return 0
+}
+
+// readCgroupFile reads and trims a file under the cgroup v2 root.
+func readCgroupFile(name string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(cgroupV2Root, name))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
@@ -0,0 +1,362 @@
+package memory
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// nodeShardBits is the number of low bits reserved for the local slot index
+// within a NUMAShardedPool's globally unique slot index.
+const nodeShardBits = 24
+
+// nodeShard holds the slots, free list, and statistics for a single NUMA node.
+type nodeShard struct {
+	nodeID    int
+	allocator *NUMAAllocator
+	data      []byte
+	slotSize  int
+	numSlots  int
+	freeList  chan int
+	inUse     []atomic.Bool
+
+	totalAllocs  atomic.Uint64
+	totalFrees   atomic.Uint64
+	peakUsage    atomic.Int32
+	currentUsage atomic.Int32
+}
+
+// NUMAShardedPool is a memory pool with one node-local shard per NUMA node.
+// Acquire routes callers to their local shard first, falling back to the
+// nearest neighbor nodes (by /sys distance) when the local shard is
+// exhausted, which avoids the cross-socket memory traffic a single shared
+// pool incurs when a caller on one node draws a slot another node wrote.
+//
+// server.NewServer constructs this instead of MemoryPool when
+// cfg.NUMAEnabled is set, via the shared Pool interface - see
+// server.go's memPool construction and memory.Pool's doc comment.
+type NUMAShardedPool struct {
+	shards     map[int]*nodeShard
+	tryOrder   map[int][]int // nodeID -> ordered list of nodeIDs to try (self first)
+	cpuToNode  map[int]int
+	slotSize   int
+	numSlots   int
+
+	mu sync.RWMutex
+}
+
+// NewNUMAShardedPool creates a memory pool sharded across NUMA nodes.
+// It falls back to a single "node 0" shard on systems without NUMA.
+func NewNUMAShardedPool(config PoolConfig) (*NUMAShardedPool, error) {
+	info := GetNUMAInfo()
+
+	nodeIDs := make([]int, 0, len(info.CPUsPerNode))
+	for node := range info.CPUsPerNode {
+		nodeIDs = append(nodeIDs, node)
+	}
+	if len(nodeIDs) == 0 {
+		nodeIDs = []int{0}
+	}
+	sort.Ints(nodeIDs)
+
+	slotsPerNode := config.NumSlots / len(nodeIDs)
+	if slotsPerNode == 0 {
+		slotsPerNode = 1
+	}
+
+	// See roundUpToCacheLine in pool.go: keeps slot boundaries off shared
+	// cache lines.
+	config.SlotSize = roundUpToCacheLine(config.SlotSize)
+
+	pool := &NUMAShardedPool{
+		shards:    make(map[int]*nodeShard, len(nodeIDs)),
+		tryOrder:  make(map[int][]int, len(nodeIDs)),
+		cpuToNode: make(map[int]int),
+		slotSize:  config.SlotSize,
+		numSlots:  slotsPerNode * len(nodeIDs),
+	}
+
+	for node, cpus := range info.CPUsPerNode {
+		for _, cpu := range cpus {
+			pool.cpuToNode[cpu] = node
+		}
+	}
+
+	for _, node := range nodeIDs {
+		shard, err := newNodeShard(node, slotsPerNode, config)
+		if err != nil {
+			return nil, err
+		}
+		pool.shards[node] = shard
+		pool.tryOrder[node] = orderByDistance(node, nodeIDs)
+	}
+
+	return pool, nil
+}
+
+// newNodeShard allocates and touch-faults a slab bound to the given NUMA node.
+func newNodeShard(nodeID, numSlots int, config PoolConfig) (*nodeShard, error) {
+	allocator, err := NewNUMAAllocator(nodeID, config.UseHugepages)
+	if err != nil {
+		return nil, err
+	}
+
+	totalSize := numSlots * config.SlotSize
+
+	shard := &nodeShard{
+		nodeID:    nodeID,
+		allocator: allocator,
+		slotSize:  config.SlotSize,
+		numSlots:  numSlots,
+		freeList:  make(chan int, numSlots),
+		inUse:     make([]atomic.Bool, numSlots),
+	}
+
+	if config.Preallocate {
+		data, err := allocator.AllocateAligned(totalSize)
+		if err != nil {
+			return nil, err
+		}
+		shard.data = data
+
+		// Touch-fault pages on a thread bound to this node so they're
+		// physically backed by node-local memory.
+		if err := touchOnNode(allocator, data); err != nil {
+			return nil, err
+		}
+	}
+
+	for i := 0; i < numSlots; i++ {
+		shard.freeList <- i
+	}
+
+	return shard, nil
+}
+
+// touchOnNode locks the calling goroutine to an OS thread, binds it to the
+// shard's NUMA node, and touches every page so the kernel faults them in
+// node-local instead of wherever the caller happens to be running.
+func touchOnNode(allocator *NUMAAllocator, data []byte) error {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	if err := allocator.BindToNode(); err != nil {
+		// Non-fatal: binding is a best-effort placement hint.
+		_ = err
+	}
+
+	for i := 0; i < len(data); i += os.Getpagesize() {
+		data[i] = 0
+	}
+
+	return nil
+}
+
+// orderByDistance returns nodeIDs ordered by ascending /sys distance from
+// self, with self always first. Nodes without distance information sort
+// after nodes with known distances, in numeric order.
+func orderByDistance(self int, nodeIDs []int) []int {
+	distances := readNodeDistances(self)
+
+	order := make([]int, len(nodeIDs))
+	copy(order, nodeIDs)
+
+	sort.SliceStable(order, func(i, j int) bool {
+		if order[i] == self {
+			return true
+		}
+		if order[j] == self {
+			return false
+		}
+		di, diOK := distances[order[i]]
+		dj, djOK := distances[order[j]]
+		if diOK != djOK {
+			return diOK
+		}
+		return di < dj
+	})
+
+	return order
+}
+
+// readNodeDistances reads /sys/devices/system/node/nodeN/distance, which
+// contains a space-separated list of distances to every node, indexed by
+// node ID.
+func readNodeDistances(nodeID int) map[int]int {
+	path := filepath.Join("/sys/devices/system/node", "node"+strconv.Itoa(nodeID), "distance")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	distances := make(map[int]int)
+	for i, field := range strings.Fields(string(data)) {
+		if d, err := strconv.Atoi(field); err == nil {
+			distances[i] = d
+		}
+	}
+	return distances
+}
+
+// currentCPUNode returns the NUMA node of the CPU the caller is currently
+// running on, using the same getcpu syscall as getCurrentNUMANode.
+func (p *NUMAShardedPool) currentCPUNode() int {
+	var cpu, node uint
+	_, _, errno := unix.Syscall(unix.SYS_GETCPU, uintptr(unsafe.Pointer(&cpu)), uintptr(unsafe.Pointer(&node)), 0)
+	if errno != 0 {
+		return 0
+	}
+
+	if n, ok := p.cpuToNode[int(cpu)]; ok {
+		return n
+	}
+	return 0
+}
+
+// Acquire gets a free slot, preferring the caller's local NUMA node and
+// falling back to neighbor nodes in distance order. The returned index
+// encodes the owning node as (nodeID<<24 | localIdx) for Release.
+func (p *NUMAShardedPool) Acquire() (int, []byte, error) {
+	localNode := p.currentCPUNode()
+
+	order, ok := p.tryOrder[localNode]
+	if !ok {
+		// localNode has no shard (shouldn't normally happen); try them all.
+		for node := range p.shards {
+			order = append(order, node)
+		}
+	}
+
+	for _, node := range order {
+		shard := p.shards[node]
+		select {
+		case localIdx := <-shard.freeList:
+			if !shard.inUse[localIdx].CompareAndSwap(false, true) {
+				return 0, nil, ErrInvalidSlot
+			}
+
+			shard.totalAllocs.Add(1)
+			current := shard.currentUsage.Add(1)
+			for {
+				peak := shard.peakUsage.Load()
+				if current <= peak || shard.peakUsage.CompareAndSwap(peak, current) {
+					break
+				}
+			}
+
+			start := localIdx * shard.slotSize
+			end := start + shard.slotSize
+			return encodeSlot(node, localIdx), shard.data[start:end], nil
+
+		default:
+			continue
+		}
+	}
+
+	return 0, nil, ErrPoolExhausted
+}
+
+// Release returns a slot to its owning node's shard.
+func (p *NUMAShardedPool) Release(idx int) error {
+	node, localIdx := decodeSlot(idx)
+
+	shard, ok := p.shards[node]
+	if !ok || localIdx < 0 || localIdx >= shard.numSlots {
+		return ErrInvalidSlot
+	}
+
+	if !shard.inUse[localIdx].CompareAndSwap(true, false) {
+		return ErrSlotNotInUse
+	}
+
+	start := localIdx * shard.slotSize
+	end := start + shard.slotSize
+	for i := start; i < end; i++ {
+		shard.data[i] = 0
+	}
+
+	shard.freeList <- localIdx
+	shard.totalFrees.Add(1)
+	shard.currentUsage.Add(-1)
+
+	return nil
+}
+
+// PerNodeStats returns per-node pool statistics, keyed by NUMA node ID.
+func (p *NUMAShardedPool) PerNodeStats() map[int]PoolStats {
+	stats := make(map[int]PoolStats, len(p.shards))
+	for node, shard := range p.shards {
+		freeCount := len(shard.freeList)
+		stats[node] = PoolStats{
+			TotalSlots:  shard.numSlots,
+			FreeSlots:   freeCount,
+			UsedSlots:   shard.numSlots - freeCount,
+			TotalAllocs: shard.totalAllocs.Load(),
+			TotalFrees:  shard.totalFrees.Load(),
+			PeakUsage:   shard.peakUsage.Load(),
+			SlotSize:    shard.slotSize,
+			TotalMemory: shard.numSlots * shard.slotSize,
+		}
+	}
+	return stats
+}
+
+// AggregateStats sums every shard's stats into one PoolStats, for callers
+// that only want a single combined view (the JSON/protobuf status
+// responses, whose MemoryPoolStatus shape has no per-node breakdown).
+// PeakUsage is summed rather than maxed, since peaks on different nodes
+// can coincide and a caller totalling "how much is in use right now"
+// needs the sum of per-node peaks to not undercount that case.
+func (p *NUMAShardedPool) AggregateStats() PoolStats {
+	var total PoolStats
+	for _, shard := range p.shards {
+		freeCount := len(shard.freeList)
+		total.TotalSlots += shard.numSlots
+		total.FreeSlots += freeCount
+		total.UsedSlots += shard.numSlots - freeCount
+		total.TotalAllocs += shard.totalAllocs.Load()
+		total.TotalFrees += shard.totalFrees.Load()
+		total.PeakUsage += shard.peakUsage.Load()
+		total.SlotSize = shard.slotSize
+		total.TotalMemory += shard.numSlots * shard.slotSize
+	}
+	return total
+}
+
+// Close releases all memory allocated by every shard.
+func (p *NUMAShardedPool) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, shard := range p.shards {
+		if shard.data != nil {
+			if err := shard.allocator.Free(shard.data); err != nil {
+				return err
+			}
+			shard.data = nil
+		}
+		close(shard.freeList)
+	}
+
+	return nil
+}
+
+// encodeSlot packs a node ID and local slot index into a globally unique
+// slot index.
+func encodeSlot(nodeID, localIdx int) int {
+	return (nodeID << nodeShardBits) | localIdx
+}
+
+// decodeSlot unpacks a globally unique slot index into its node ID and
+// local slot index.
+func decodeSlot(idx int) (nodeID, localIdx int) {
+	return idx >> nodeShardBits, idx & ((1 << nodeShardBits) - 1)
+}
@@ -0,0 +1,87 @@
+package memory
+
+import "testing"
+
+// TestNUMAShardedPoolFallback exercises the single-"node 0"-shard path
+// NewNUMAShardedPool falls back to on hosts without NUMA (the common case
+// for CI and most sandboxes), covering Acquire/Release/AggregateStats/
+// PerNodeStats end to end.
+func TestNUMAShardedPoolFallback(t *testing.T) {
+	pool, err := NewNUMAShardedPool(PoolConfig{
+		NumSlots:    4,
+		SlotSize:    64,
+		Preallocate: true,
+	})
+	if err != nil {
+		t.Fatalf("NewNUMAShardedPool: %v", err)
+	}
+	defer pool.Close()
+
+	idx, data, err := pool.Acquire()
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	if len(data) != roundUpToCacheLine(64) {
+		t.Errorf("Acquire data len = %d, want %d", len(data), roundUpToCacheLine(64))
+	}
+
+	if agg := pool.AggregateStats(); agg.TotalSlots != 4 || agg.UsedSlots != 1 || agg.FreeSlots != 3 {
+		t.Errorf("AggregateStats after one Acquire = %+v, want TotalSlots=4 UsedSlots=1 FreeSlots=3", agg)
+	}
+
+	perNode := pool.PerNodeStats()
+	nodeStats, ok := perNode[0]
+	if !ok {
+		t.Fatalf("PerNodeStats missing node 0: %+v", perNode)
+	}
+	if nodeStats.UsedSlots != 1 {
+		t.Errorf("PerNodeStats[0].UsedSlots = %d, want 1", nodeStats.UsedSlots)
+	}
+
+	if err := pool.Release(idx); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	if got := pool.AggregateStats().UsedSlots; got != 0 {
+		t.Errorf("AggregateStats.UsedSlots after Release = %d, want 0", got)
+	}
+}
+
+// TestNUMAShardedPoolExhausted checks Acquire returns ErrPoolExhausted once
+// every shard's slots are checked out, the same contract MemoryPool.Acquire
+// has.
+func TestNUMAShardedPoolExhausted(t *testing.T) {
+	pool, err := NewNUMAShardedPool(PoolConfig{
+		NumSlots:    1,
+		SlotSize:    64,
+		Preallocate: true,
+	})
+	if err != nil {
+		t.Fatalf("NewNUMAShardedPool: %v", err)
+	}
+	defer pool.Close()
+
+	if _, _, err := pool.Acquire(); err != nil {
+		t.Fatalf("first Acquire: %v", err)
+	}
+	if _, _, err := pool.Acquire(); err != ErrPoolExhausted {
+		t.Errorf("second Acquire = %v, want ErrPoolExhausted", err)
+	}
+}
+
+// TestNUMAShardedPoolReleaseInvalidSlot checks Release rejects an index
+// that doesn't decode to any existing shard.
+func TestNUMAShardedPoolReleaseInvalidSlot(t *testing.T) {
+	pool, err := NewNUMAShardedPool(PoolConfig{
+		NumSlots:    1,
+		SlotSize:    64,
+		Preallocate: true,
+	})
+	if err != nil {
+		t.Fatalf("NewNUMAShardedPool: %v", err)
+	}
+	defer pool.Close()
+
+	if err := pool.Release(encodeSlot(99, 0)); err != ErrInvalidSlot {
+		t.Errorf("Release of unknown node = %v, want ErrInvalidSlot", err)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"sync"
 	"sync/atomic"
+
+	"github.com/penguintechinc/project-template/services/go-backend/internal/cpu"
 )
 
 var (
@@ -16,6 +18,20 @@ var (
 	ErrSlotNotInUse = errors.New("slot not in use")
 )
 
+// Pool is the common interface MemoryPool and NUMAShardedPool both satisfy,
+// so callers (server.Handlers, grpcsrv.Server, grpcserver.Server,
+// NewBufferPool) can be handed either a single-node or NUMA-sharded pool
+// interchangeably. AggregateStats gives the one-PoolStats view the
+// existing JSON/protobuf status responses are shaped around; PerNodeStats
+// gives the per-node breakdown metrics.UpdateMemoryPoolStats reports.
+type Pool interface {
+	Acquire() (int, []byte, error)
+	Release(idx int) error
+	AggregateStats() PoolStats
+	PerNodeStats() map[int]PoolStats
+	Close() error
+}
+
 // MemoryPool provides pre-allocated memory slots for zero-copy operations.
 type MemoryPool struct {
 	allocator *NUMAAllocator
@@ -61,6 +77,26 @@ func NewMemoryPool(config PoolConfig) (*MemoryPool, error) {
 		return nil, err
 	}
 
+	// Clamp the slab to what the cgroup v2 memory limit allows, if any, so
+	// a container doesn't get OOM-killed by a pool sized for bare metal.
+	if config.Preallocate {
+		if limits, cgErr := DetectCgroupLimits(); cgErr == nil && limits.Available && limits.MemoryMaxBytes > 0 {
+			available := limits.MemoryMaxBytes - limits.MemoryCurrentBytes
+			if wanted := int64(config.NumSlots) * int64(config.SlotSize); available > 0 && wanted > available {
+				config.NumSlots = int(available / int64(config.SlotSize))
+				if config.NumSlots < 1 {
+					config.NumSlots = 1
+				}
+			}
+		}
+	}
+
+	// Round the slot size up to a cache-line multiple so every slot's
+	// first byte starts on a cache line and the previous slot's last
+	// byte never shares one with it; otherwise two goroutines pinned to
+	// adjacent slots on the XDP RX hot path can false-share a line.
+	config.SlotSize = roundUpToCacheLine(config.SlotSize)
+
 	totalSize := config.NumSlots * config.SlotSize
 
 	// Allocate contiguous memory region
@@ -198,6 +234,20 @@ func (p *MemoryPool) Stats() PoolStats {
 	}
 }
 
+// AggregateStats returns the same stats as Stats. It exists so MemoryPool
+// satisfies the Pool interface under the same method name NUMAShardedPool
+// uses for its single combined-across-shards view.
+func (p *MemoryPool) AggregateStats() PoolStats {
+	return p.Stats()
+}
+
+// PerNodeStats returns MemoryPool's stats keyed under node 0, so callers
+// that want a per-node breakdown (metrics.UpdateMemoryPoolStats) get a
+// uniform map whether the underlying pool is NUMA-sharded or not.
+func (p *MemoryPool) PerNodeStats() map[int]PoolStats {
+	return map[int]PoolStats{0: p.Stats()}
+}
+
 // Close releases all memory allocated by the pool.
 func (p *MemoryPool) Close() error {
 	p.mu.Lock()
@@ -213,3 +263,11 @@ func (p *MemoryPool) Close() error {
 	close(p.freeList)
 	return nil
 }
+
+// roundUpToCacheLine rounds n up to the next multiple of cpu.CacheLineSize.
+func roundUpToCacheLine(n int) int {
+	if rem := n % cpu.CacheLineSize; rem != 0 {
+		n += cpu.CacheLineSize - rem
+	}
+	return n
+}
@@ -2,8 +2,27 @@
 package metrics
 
 import (
+	"strconv"
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/penguintechinc/project-template/services/go-backend/internal/memory"
+)
+
+// Native histogram settings shared by the high-frequency HTTP and XDP
+// latency histograms. A bucket factor of 1.1 selects schema 3: buckets
+// about 9% wide, 8 per doubling of the observed value. Observations spread
+// over the full nanosecond-to-second range would fill about 240 buckets,
+// more than the 160-bucket cap; past the cap client_golang resets the
+// histogram if an hour (the min reset duration) has passed since the last
+// reset, and otherwise halves its resolution.
+const (
+	nativeHistogramBucketFactor     = 1.1
+	nativeHistogramMaxBucketNumber  = 160
+	nativeHistogramMinResetDuration = time.Hour
 )
 
 // Metrics holds all Prometheus metrics for the application.
@@ -12,6 +31,13 @@ type Metrics struct {
 	HTTPRequestsTotal    *prometheus.CounterVec
 	HTTPRequestDuration  *prometheus.HistogramVec
 	HTTPActiveRequests   prometheus.Gauge
+	HTTPRequestCPUSeconds  *prometheus.HistogramVec
+	HTTPRequestAllocBytes  *prometheus.HistogramVec
+
+	// gRPC metrics (internal/grpcserver)
+	GRPCRequestsTotal   *prometheus.CounterVec
+	GRPCRequestDuration *prometheus.HistogramVec
+	GRPCActiveRequests  prometheus.Gauge
 
 	// XDP metrics
 	XDPPacketsReceived   prometheus.Counter
@@ -21,35 +47,69 @@ type Metrics struct {
 	XDPBytesSent         prometheus.Counter
 	XDPProcessingTime    prometheus.Histogram
 
-	// Memory pool metrics
-	MemoryPoolTotal      prometheus.Gauge
-	MemoryPoolUsed       prometheus.Gauge
-	MemoryPoolFree       prometheus.Gauge
+	// Memory pool metrics (labeled by NUMA node for NUMAShardedPool)
+	MemoryPoolTotal      *prometheus.GaugeVec
+	MemoryPoolUsed       *prometheus.GaugeVec
+	MemoryPoolFree       *prometheus.GaugeVec
 	MemoryPoolAllocations prometheus.Counter
 	MemoryPoolReleases   prometheus.Counter
-	MemoryPoolPeakUsage  prometheus.Gauge
+	MemoryPoolPeakUsage  *prometheus.GaugeVec
 
 	// NUMA metrics
 	NUMANodeID           prometheus.Gauge
 	NUMAAvailable        prometheus.Gauge
 	NUMAMemoryMB         *prometheus.GaugeVec
 
-	// System metrics
-	GoRoutines           prometheus.Gauge
-	HeapAlloc            prometheus.Gauge
-	HeapSys              prometheus.Gauge
-	GCPauseNS            prometheus.Gauge
+	// Cgroup v2 metrics
+	CgroupMemoryMaxBytes     prometheus.Gauge
+	CgroupMemoryCurrentBytes prometheus.Gauge
+	CgroupCPUQuotaCPUs       prometheus.Gauge
+	CgroupPressureSomeAvg10  *prometheus.GaugeVec
+
+	// registry is private: Go runtime and process metrics are registered
+	// directly via collectors.NewGoCollector/NewProcessCollector rather
+	// than hand-rolled gauges here.
+	registry *prometheus.Registry
+}
+
+// metricsOptions holds the configurable bits of NewMetrics.
+type metricsOptions struct {
+	withoutDefaultCollectors bool
 }
 
-// NewMetrics creates and registers all metrics.
-func NewMetrics(namespace string) *Metrics {
+// MetricsOption configures NewMetrics.
+type MetricsOption func(*metricsOptions)
+
+// WithoutDefaultCollectors skips registering the standard Go runtime and
+// process collectors, so tests get a registry containing only this
+// package's own metrics.
+func WithoutDefaultCollectors() MetricsOption {
+	return func(o *metricsOptions) {
+		o.withoutDefaultCollectors = true
+	}
+}
+
+// NewMetrics creates and registers all metrics on a private registry. Use
+// Registry() to scrape it or to register additional collectors alongside
+// without touching the global default registry.
+func NewMetrics(namespace string, opts ...MetricsOption) *Metrics {
 	if namespace == "" {
 		namespace = "go_backend"
 	}
 
+	var cfg metricsOptions
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	registry := prometheus.NewRegistry()
+	factory := promauto.With(registry)
+
 	m := &Metrics{
+		registry: registry,
+
 		// HTTP metrics
-		HTTPRequestsTotal: promauto.NewCounterVec(
+		HTTPRequestsTotal: factory.NewCounterVec(
 			prometheus.CounterOpts{
 				Namespace: namespace,
 				Name:      "http_requests_total",
@@ -58,17 +118,23 @@ func NewMetrics(namespace string) *Metrics {
 			[]string{"method", "endpoint", "status"},
 		),
 
-		HTTPRequestDuration: promauto.NewHistogramVec(
+		HTTPRequestDuration: factory.NewHistogramVec(
 			prometheus.HistogramOpts{
 				Namespace: namespace,
 				Name:      "http_request_duration_seconds",
 				Help:      "HTTP request duration in seconds",
 				Buckets:   prometheus.DefBuckets,
+
+				// Sparse native histogram, kept alongside the classic
+				// buckets above so old and new scrapers both work.
+				NativeHistogramBucketFactor:     nativeHistogramBucketFactor,
+				NativeHistogramMaxBucketNumber:  nativeHistogramMaxBucketNumber,
+				NativeHistogramMinResetDuration: nativeHistogramMinResetDuration,
 			},
 			[]string{"method", "endpoint"},
 		),
 
-		HTTPActiveRequests: promauto.NewGauge(
+		HTTPActiveRequests: factory.NewGauge(
 			prometheus.GaugeOpts{
 				Namespace: namespace,
 				Name:      "http_active_requests",
@@ -76,8 +142,60 @@ func NewMetrics(namespace string) *Metrics {
 			},
 		),
 
+		HTTPRequestCPUSeconds: factory.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      "http_request_cpu_seconds",
+				Help:      "Per-request CPU time (user+sys), requires resource accounting to be enabled",
+				Buckets:   prometheus.ExponentialBuckets(0.0001, 4, 10),
+			},
+			[]string{"method", "endpoint"},
+		),
+
+		HTTPRequestAllocBytes: factory.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      "http_request_alloc_bytes",
+				Help:      "Per-request heap bytes allocated, requires resource accounting to be enabled",
+				Buckets:   prometheus.ExponentialBuckets(256, 4, 10),
+			},
+			[]string{"method", "endpoint"},
+		),
+
+		// gRPC metrics
+		GRPCRequestsTotal: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "grpc_requests_total",
+				Help:      "Total number of gRPC requests",
+			},
+			[]string{"method", "code"},
+		),
+
+		GRPCRequestDuration: factory.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      "grpc_request_duration_seconds",
+				Help:      "gRPC request duration in seconds",
+				Buckets:   prometheus.DefBuckets,
+
+				NativeHistogramBucketFactor:     nativeHistogramBucketFactor,
+				NativeHistogramMaxBucketNumber:  nativeHistogramMaxBucketNumber,
+				NativeHistogramMinResetDuration: nativeHistogramMinResetDuration,
+			},
+			[]string{"method"},
+		),
+
+		GRPCActiveRequests: factory.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "grpc_active_requests",
+				Help:      "Number of active gRPC requests (unary and streaming)",
+			},
+		),
+
 		// XDP metrics
-		XDPPacketsReceived: promauto.NewCounter(
+		XDPPacketsReceived: factory.NewCounter(
 			prometheus.CounterOpts{
 				Namespace: namespace,
 				Name:      "xdp_packets_received_total",
@@ -85,7 +203,7 @@ func NewMetrics(namespace string) *Metrics {
 			},
 		),
 
-		XDPPacketsSent: promauto.NewCounter(
+		XDPPacketsSent: factory.NewCounter(
 			prometheus.CounterOpts{
 				Namespace: namespace,
 				Name:      "xdp_packets_sent_total",
@@ -93,7 +211,7 @@ func NewMetrics(namespace string) *Metrics {
 			},
 		),
 
-		XDPPacketsDropped: promauto.NewCounter(
+		XDPPacketsDropped: factory.NewCounter(
 			prometheus.CounterOpts{
 				Namespace: namespace,
 				Name:      "xdp_packets_dropped_total",
@@ -101,7 +219,7 @@ func NewMetrics(namespace string) *Metrics {
 			},
 		),
 
-		XDPBytesReceived: promauto.NewCounter(
+		XDPBytesReceived: factory.NewCounter(
 			prometheus.CounterOpts{
 				Namespace: namespace,
 				Name:      "xdp_bytes_received_total",
@@ -109,7 +227,7 @@ func NewMetrics(namespace string) *Metrics {
 			},
 		),
 
-		XDPBytesSent: promauto.NewCounter(
+		XDPBytesSent: factory.NewCounter(
 			prometheus.CounterOpts{
 				Namespace: namespace,
 				Name:      "xdp_bytes_sent_total",
@@ -117,41 +235,51 @@ func NewMetrics(namespace string) *Metrics {
 			},
 		),
 
-		XDPProcessingTime: promauto.NewHistogram(
+		XDPProcessingTime: factory.NewHistogram(
 			prometheus.HistogramOpts{
 				Namespace: namespace,
 				Name:      "xdp_processing_time_nanoseconds",
 				Help:      "Packet processing time in nanoseconds",
 				Buckets:   []float64{100, 500, 1000, 5000, 10000, 50000, 100000},
+
+				// The classic buckets above top out at 100µs and lose all
+				// tail resolution; the native histogram covers the full
+				// range exponentially so p99.9 outliers are still visible.
+				NativeHistogramBucketFactor:     nativeHistogramBucketFactor,
+				NativeHistogramMaxBucketNumber:  nativeHistogramMaxBucketNumber,
+				NativeHistogramMinResetDuration: nativeHistogramMinResetDuration,
 			},
 		),
 
 		// Memory pool metrics
-		MemoryPoolTotal: promauto.NewGauge(
+		MemoryPoolTotal: factory.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Namespace: namespace,
 				Name:      "memory_pool_slots_total",
 				Help:      "Total number of memory pool slots",
 			},
+			[]string{"node"},
 		),
 
-		MemoryPoolUsed: promauto.NewGauge(
+		MemoryPoolUsed: factory.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Namespace: namespace,
 				Name:      "memory_pool_slots_used",
 				Help:      "Number of memory pool slots in use",
 			},
+			[]string{"node"},
 		),
 
-		MemoryPoolFree: promauto.NewGauge(
+		MemoryPoolFree: factory.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Namespace: namespace,
 				Name:      "memory_pool_slots_free",
 				Help:      "Number of free memory pool slots",
 			},
+			[]string{"node"},
 		),
 
-		MemoryPoolAllocations: promauto.NewCounter(
+		MemoryPoolAllocations: factory.NewCounter(
 			prometheus.CounterOpts{
 				Namespace: namespace,
 				Name:      "memory_pool_allocations_total",
@@ -159,7 +287,7 @@ func NewMetrics(namespace string) *Metrics {
 			},
 		),
 
-		MemoryPoolReleases: promauto.NewCounter(
+		MemoryPoolReleases: factory.NewCounter(
 			prometheus.CounterOpts{
 				Namespace: namespace,
 				Name:      "memory_pool_releases_total",
@@ -167,16 +295,17 @@ func NewMetrics(namespace string) *Metrics {
 			},
 		),
 
-		MemoryPoolPeakUsage: promauto.NewGauge(
+		MemoryPoolPeakUsage: factory.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Namespace: namespace,
 				Name:      "memory_pool_peak_usage",
 				Help:      "Peak memory pool usage",
 			},
+			[]string{"node"},
 		),
 
 		// NUMA metrics
-		NUMANodeID: promauto.NewGauge(
+		NUMANodeID: factory.NewGauge(
 			prometheus.GaugeOpts{
 				Namespace: namespace,
 				Name:      "numa_node_id",
@@ -184,7 +313,7 @@ func NewMetrics(namespace string) *Metrics {
 			},
 		),
 
-		NUMAAvailable: promauto.NewGauge(
+		NUMAAvailable: factory.NewGauge(
 			prometheus.GaugeOpts{
 				Namespace: namespace,
 				Name:      "numa_available",
@@ -192,7 +321,7 @@ func NewMetrics(namespace string) *Metrics {
 			},
 		),
 
-		NUMAMemoryMB: promauto.NewGaugeVec(
+		NUMAMemoryMB: factory.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Namespace: namespace,
 				Name:      "numa_memory_mb",
@@ -201,55 +330,129 @@ func NewMetrics(namespace string) *Metrics {
 			[]string{"node"},
 		),
 
-		// System metrics
-		GoRoutines: promauto.NewGauge(
+		// Cgroup v2 metrics
+		CgroupMemoryMaxBytes: factory.NewGauge(
 			prometheus.GaugeOpts{
 				Namespace: namespace,
-				Name:      "goroutines",
-				Help:      "Number of goroutines",
+				Name:      "cgroup_memory_max_bytes",
+				Help:      "Cgroup v2 memory.max for this process, or -1 if unlimited",
 			},
 		),
 
-		HeapAlloc: promauto.NewGauge(
+		CgroupMemoryCurrentBytes: factory.NewGauge(
 			prometheus.GaugeOpts{
 				Namespace: namespace,
-				Name:      "heap_alloc_bytes",
-				Help:      "Heap allocation in bytes",
+				Name:      "cgroup_memory_current_bytes",
+				Help:      "Cgroup v2 memory.current for this process",
 			},
 		),
 
-		HeapSys: promauto.NewGauge(
+		CgroupCPUQuotaCPUs: factory.NewGauge(
 			prometheus.GaugeOpts{
 				Namespace: namespace,
-				Name:      "heap_sys_bytes",
-				Help:      "Heap system memory in bytes",
+				Name:      "cgroup_cpu_quota_cpus",
+				Help:      "Effective cgroup v2 CPU quota in fractional CPUs, or -1 if unlimited",
 			},
 		),
 
-		GCPauseNS: promauto.NewGauge(
+		CgroupPressureSomeAvg10: factory.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Namespace: namespace,
-				Name:      "gc_pause_ns",
-				Help:      "Last GC pause duration in nanoseconds",
+				Name:      "cgroup_pressure_some_avg10",
+				Help:      "Cgroup v2 PSI \"some\" pressure, 10s average, by resource",
 			},
+			[]string{"resource"},
 		),
 	}
 
+	if !cfg.withoutDefaultCollectors {
+		registry.MustRegister(
+			collectors.NewGoCollector(
+				collectors.WithGoCollections(collectors.GoRuntimeMetricsCollection),
+			),
+			collectors.NewProcessCollector(collectors.ProcessCollectorOpts{Namespace: namespace}),
+		)
+	}
+
 	return m
 }
 
-// RecordHTTPRequest records metrics for an HTTP request.
-func (m *Metrics) RecordHTTPRequest(method, endpoint, status string, durationSeconds float64) {
+// Registry returns the Prometheus registry backing these metrics. Callers
+// can register additional collectors on it directly instead of touching
+// the global default registry, and it's what should be passed to
+// promhttp.HandlerFor when serving /metrics.
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+// RecordHTTPRequest records metrics for an HTTP request. If traceID is
+// non-empty (an OpenTelemetry span was active for the request), the
+// observation carries it as an exemplar so Prometheus can link the latency
+// bucket back to the trace.
+func (m *Metrics) RecordHTTPRequest(method, endpoint, status string, durationSeconds float64, traceID string) {
 	m.HTTPRequestsTotal.WithLabelValues(method, endpoint, status).Inc()
-	m.HTTPRequestDuration.WithLabelValues(method, endpoint).Observe(durationSeconds)
+
+	observer := m.HTTPRequestDuration.WithLabelValues(method, endpoint)
+	if traceID != "" {
+		if exemplarObserver, ok := observer.(prometheus.ExemplarObserver); ok {
+			exemplarObserver.ObserveWithExemplar(durationSeconds, prometheus.Labels{"trace_id": traceID})
+			return
+		}
+	}
+	observer.Observe(durationSeconds)
+}
+
+// RecordHTTPRequestResources records the CPU time and heap bytes allocated
+// while handling a request. Only called when resource accounting is
+// enabled, since measuring it costs a getrusage syscall and an OS thread
+// lock per request.
+func (m *Metrics) RecordHTTPRequestResources(method, endpoint string, cpuSeconds, allocBytes float64) {
+	m.HTTPRequestCPUSeconds.WithLabelValues(method, endpoint).Observe(cpuSeconds)
+	m.HTTPRequestAllocBytes.WithLabelValues(method, endpoint).Observe(allocBytes)
+}
+
+// RecordGRPCRequest records metrics for one gRPC call (unary or, once the
+// stream finishes, an entire streaming call). code is the string form of
+// the call's final grpc/codes.Code, e.g. "OK" or "NotFound".
+func (m *Metrics) RecordGRPCRequest(method, code string, durationSeconds float64) {
+	m.GRPCRequestsTotal.WithLabelValues(method, code).Inc()
+	m.GRPCRequestDuration.WithLabelValues(method).Observe(durationSeconds)
+}
+
+// RecordXDPProcessing records packet processing latency in nanoseconds,
+// attaching flowID as an exemplar so a slow bucket can be traced back to
+// the flow that caused it.
+func (m *Metrics) RecordXDPProcessing(nanos float64, flowID string) {
+	if flowID != "" {
+		if exemplarObserver, ok := interface{}(m.XDPProcessingTime).(prometheus.ExemplarObserver); ok {
+			exemplarObserver.ObserveWithExemplar(nanos, prometheus.Labels{"flow_id": flowID})
+			return
+		}
+	}
+	m.XDPProcessingTime.Observe(nanos)
+}
+
+// UpdateMemoryPoolStats updates memory pool metrics, one gauge set per NUMA
+// node. Callers with a single, non-sharded pool can pass a map with one
+// entry (conventionally keyed by node 0).
+func (m *Metrics) UpdateMemoryPoolStats(perNode map[int]memory.PoolStats) {
+	for node, stats := range perNode {
+		label := strconv.Itoa(node)
+		m.MemoryPoolTotal.WithLabelValues(label).Set(float64(stats.TotalSlots))
+		m.MemoryPoolUsed.WithLabelValues(label).Set(float64(stats.UsedSlots))
+		m.MemoryPoolFree.WithLabelValues(label).Set(float64(stats.FreeSlots))
+		m.MemoryPoolPeakUsage.WithLabelValues(label).Set(float64(stats.PeakUsage))
+	}
 }
 
-// UpdateMemoryPoolStats updates memory pool metrics.
-func (m *Metrics) UpdateMemoryPoolStats(total, used, free int, allocs, releases uint64, peak int32) {
-	m.MemoryPoolTotal.Set(float64(total))
-	m.MemoryPoolUsed.Set(float64(used))
-	m.MemoryPoolFree.Set(float64(free))
-	m.MemoryPoolPeakUsage.Set(float64(peak))
+// UpdateCgroupStats updates cgroup v2 resource limit and pressure metrics.
+func (m *Metrics) UpdateCgroupStats(limits memory.CgroupLimits) {
+	m.CgroupMemoryMaxBytes.Set(float64(limits.MemoryMaxBytes))
+	m.CgroupMemoryCurrentBytes.Set(float64(limits.MemoryCurrentBytes))
+	m.CgroupCPUQuotaCPUs.Set(limits.CPUQuotaCPUs)
+	m.CgroupPressureSomeAvg10.WithLabelValues("memory").Set(limits.PressureMemorySomeAvg10)
+	m.CgroupPressureSomeAvg10.WithLabelValues("cpu").Set(limits.PressureCPUSomeAvg10)
+	m.CgroupPressureSomeAvg10.WithLabelValues("io").Set(limits.PressureIOSomeAvg10)
 }
 
 // UpdateNUMAStats updates NUMA-related metrics.
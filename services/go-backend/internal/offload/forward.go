@@ -0,0 +1,69 @@
+package offload
+
+import (
+	"fmt"
+
+	"github.com/penguintechinc/project-template/services/go-backend/internal/memory"
+	"github.com/penguintechinc/project-template/services/go-backend/internal/xdp"
+)
+
+// Forward is the userspace fallback for a UDP packet the dispatcher didn't
+// rewrite itself, either because the allocation isn't in relay_allocations
+// yet or because the program is running without XDP at all (see
+// xdp.IsXDPSupported). It mirrors the in-kernel rewrite in dispatcher.c:
+// swap source/dest IP and UDP port for the allocation's peer, then send
+// the packet back out through sock.
+//
+// pkt is the raw frame (as returned by sock.Receive), starting at the
+// Ethernet header. Forward copies it into a scratch buffer from bufPool
+// rather than rewriting the UMEM frame in place, since that frame is owned
+// by the fill ring until the caller returns it via sock.ReturnFrame.
+func (r *Relay) Forward(sock *xdp.XDPSocket, bufPool *memory.BufferPool, pkt []byte, id uint32) error {
+	alloc, exists := r.Allocation(id)
+	if !exists {
+		return fmt.Errorf("%w: id %d", ErrAllocationNotFound, id)
+	}
+
+	buf, err := bufPool.Get()
+	if err != nil {
+		return fmt.Errorf("failed to acquire scratch buffer: %w", err)
+	}
+	defer bufPool.Put(buf)
+
+	if _, err := buf.Write(pkt); err != nil {
+		return err
+	}
+	scratch := buf.Data()
+
+	ipOff := xdp.EthernetHeaderSize
+	ip, err := xdp.ParseIPv4Header(scratch[ipOff:])
+	if err != nil {
+		return err
+	}
+	if ip.Protocol != xdp.IPProtoUDP {
+		return fmt.Errorf("%w: expected UDP, got protocol %d", xdp.ErrUnsupportedType, ip.Protocol)
+	}
+	udpOff := ipOff + ip.HeaderLength()
+	udp, err := xdp.ParseUDPHeader(scratch[udpOff:])
+	if err != nil {
+		return err
+	}
+
+	ip.SrcIP = alloc.Listen.IP
+	ip.DstIP = alloc.Peer.IP
+	udp.SrcPort = uint16(alloc.Listen.Port)
+	udp.DstPort = uint16(alloc.Peer.Port)
+	// UDP checksum is optional over IPv4; rather than recompute it over
+	// the rewritten pseudo-header we zero it, the same "unchecked" value
+	// the kernel accepts from any other UDP sender.
+	udp.Checksum = 0
+
+	if err := ip.Serialize(scratch[ipOff:]); err != nil {
+		return err
+	}
+	if err := udp.Serialize(scratch[udpOff:], nil, nil); err != nil {
+		return err
+	}
+
+	return sock.Send(scratch)
+}
@@ -0,0 +1,243 @@
+// Package offload implements a zero-copy UDP relay offload on top of the
+// xdp and memory packages: an allocation registered here is rewritten and
+// bounced back out by the dispatcher's XDP_TX fast path (see
+// internal/xdp/bpf/dispatcher.c) for every matching packet, bypassing the
+// Go runtime entirely. This mirrors the offload/xdp mechanism pion/turn
+// uses for TURN allocations, applied to this module's generic
+// packet-forwarding surface.
+package offload
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/cilium/ebpf"
+)
+
+// maxAllocations mirrors relay_allocations/relay_counters_map's
+// max_entries in dispatcher.c.
+const maxAllocations = 1024
+
+var (
+	// ErrAllocationExists is returned when registering an ID already in use.
+	ErrAllocationExists = errors.New("relay allocation already exists")
+	// ErrAllocationNotFound is returned when an ID has no allocation.
+	ErrAllocationNotFound = errors.New("relay allocation not found")
+	// ErrAllocationsExhausted is returned when every relay_counters_map
+	// slot is in use.
+	ErrAllocationsExhausted = errors.New("relay allocation slots exhausted")
+)
+
+// relayAllocationBPF mirrors struct relay_allocation in dispatcher.c.
+// Field order and widths must stay in lockstep with the C definition:
+// cilium/ebpf marshals it directly into the map value.
+type relayAllocationBPF struct {
+	PeerAddr   uint32
+	LocalAddr  uint32
+	PeerPort   uint16
+	LocalPort  uint16
+	CounterIdx uint32
+}
+
+// relayCountersBPF mirrors struct relay_counters in dispatcher.c.
+type relayCountersBPF struct {
+	PacketsIn  uint64
+	BytesIn    uint64
+	PacketsOut uint64
+	BytesOut   uint64
+}
+
+// Allocation describes one registered UDP relay flow: packets arriving on
+// Listen are rewritten to Peer and bounced back out. Flows the dispatcher
+// hasn't (yet) learned about fall back to the userspace path in forward.go.
+type Allocation struct {
+	ID     uint32
+	Listen *net.UDPAddr
+	Peer   *net.UDPAddr
+}
+
+// Stats holds the packet/byte counters for one allocation, summed across
+// CPUs.
+type Stats struct {
+	PacketsIn  uint64
+	BytesIn    uint64
+	PacketsOut uint64
+	BytesOut   uint64
+}
+
+// Relay manages UDP relay allocations backed by the dispatcher's
+// relay_allocations and relay_counters_map eBPF maps.
+type Relay struct {
+	allocMap    *ebpf.Map
+	countersMap *ebpf.Map
+
+	mu          sync.Mutex
+	allocations map[uint32]*Allocation
+	counterIdx  map[uint32]uint32 // allocation ID -> relay_counters_map slot
+	freeIdx     []uint32
+	nextIdx     uint32
+}
+
+// NewRelay creates a Relay backed by allocMap/countersMap, as returned by
+// an XDPProgram's RelayAllocations/RelayCounters accessors.
+func NewRelay(allocMap, countersMap *ebpf.Map) *Relay {
+	return &Relay{
+		allocMap:    allocMap,
+		countersMap: countersMap,
+		allocations: make(map[uint32]*Allocation),
+		counterIdx:  make(map[uint32]uint32),
+	}
+}
+
+// AddAllocation registers a UDP relay from listen to peer under id,
+// installing the rewrite in relay_allocations so the dispatcher can handle
+// matching packets with XDP_TX.
+func (r *Relay) AddAllocation(id uint32, listen, peer *net.UDPAddr) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.allocations[id]; exists {
+		return fmt.Errorf("%w: id %d", ErrAllocationExists, id)
+	}
+
+	idx, err := r.acquireCounterIdx()
+	if err != nil {
+		return err
+	}
+
+	key := uint16(listen.Port)
+	value := relayAllocationBPF{
+		PeerAddr:   ipToUint32(peer.IP),
+		LocalAddr:  ipToUint32(listen.IP),
+		PeerPort:   uint16(peer.Port),
+		LocalPort:  uint16(listen.Port),
+		CounterIdx: idx,
+	}
+
+	if err := r.countersMap.Put(idx, make([]relayCountersBPF, numPossibleCPU())); err != nil {
+		r.releaseCounterIdx(idx)
+		return fmt.Errorf("failed to reset relay counters: %w", err)
+	}
+	if err := r.allocMap.Put(key, value); err != nil {
+		r.releaseCounterIdx(idx)
+		return fmt.Errorf("failed to install relay allocation: %w", err)
+	}
+
+	r.allocations[id] = &Allocation{ID: id, Listen: listen, Peer: peer}
+	r.counterIdx[id] = idx
+	return nil
+}
+
+// RemoveAllocation tears down a previously registered allocation.
+func (r *Relay) RemoveAllocation(id uint32) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	alloc, exists := r.allocations[id]
+	if !exists {
+		return fmt.Errorf("%w: id %d", ErrAllocationNotFound, id)
+	}
+
+	key := uint16(alloc.Listen.Port)
+	if err := r.allocMap.Delete(key); err != nil && !errors.Is(err, ebpf.ErrKeyNotExist) {
+		return fmt.Errorf("failed to remove relay allocation: %w", err)
+	}
+
+	r.releaseCounterIdx(r.counterIdx[id])
+	delete(r.counterIdx, id)
+	delete(r.allocations, id)
+	return nil
+}
+
+// Stats returns the packet/byte counters for id, summed across CPUs.
+func (r *Relay) Stats(id uint32) (Stats, error) {
+	r.mu.Lock()
+	idx, exists := r.counterIdx[id]
+	r.mu.Unlock()
+	if !exists {
+		return Stats{}, fmt.Errorf("%w: id %d", ErrAllocationNotFound, id)
+	}
+
+	var perCPU []relayCountersBPF
+	if err := r.countersMap.Lookup(idx, &perCPU); err != nil {
+		return Stats{}, fmt.Errorf("failed to read relay counters: %w", err)
+	}
+
+	var total Stats
+	for _, c := range perCPU {
+		total.PacketsIn += c.PacketsIn
+		total.BytesIn += c.BytesIn
+		total.PacketsOut += c.PacketsOut
+		total.BytesOut += c.BytesOut
+	}
+	return total, nil
+}
+
+// Allocation returns the registered allocation for id.
+func (r *Relay) Allocation(id uint32) (*Allocation, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	alloc, exists := r.allocations[id]
+	return alloc, exists
+}
+
+// Allocations returns a snapshot of every currently registered allocation.
+func (r *Relay) Allocations() []*Allocation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	out := make([]*Allocation, 0, len(r.allocations))
+	for _, a := range r.allocations {
+		out = append(out, a)
+	}
+	return out
+}
+
+// acquireCounterIdx hands out a free relay_counters_map slot, reusing
+// released ones before growing into fresh space.
+func (r *Relay) acquireCounterIdx() (uint32, error) {
+	if n := len(r.freeIdx); n > 0 {
+		idx := r.freeIdx[n-1]
+		r.freeIdx = r.freeIdx[:n-1]
+		return idx, nil
+	}
+	if r.nextIdx >= maxAllocations {
+		return 0, ErrAllocationsExhausted
+	}
+	idx := r.nextIdx
+	r.nextIdx++
+	return idx, nil
+}
+
+func (r *Relay) releaseCounterIdx(idx uint32) {
+	r.freeIdx = append(r.freeIdx, idx)
+}
+
+// numPossibleCPU returns the number of per-CPU slots a PERCPU_ARRAY value
+// needs, falling back to 1 if the kernel can't be asked.
+func numPossibleCPU() int {
+	n, err := ebpf.PossibleCPU()
+	if err != nil || n < 1 {
+		return 1
+	}
+	return n
+}
+
+// ipToUint32 packs an IPv4 address into a uint32 the same way cilium/ebpf
+// marshals a struct field into a map value: as raw host-endian bytes. The
+// only built target is bpfel (little-endian), and dispatcher.c reads
+// ip->saddr/ip->daddr straight out of the map with no byte-swap of its
+// own, so this must produce the same four bytes cilium/ebpf would get by
+// marshaling the IP's wire-order bytes as a little-endian uint32 — which
+// is what binary.LittleEndian.Uint32 does for an address already in wire
+// (big-endian) byte order.
+func ipToUint32(ip net.IP) uint32 {
+	v4 := ip.To4()
+	if v4 == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(v4)
+}
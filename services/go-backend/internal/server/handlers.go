@@ -2,13 +2,18 @@
 package server
 
 import (
+	"fmt"
+	"net"
 	"net/http"
 	"runtime"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/penguintechinc/project-template/services/go-backend/internal/memory"
+	"github.com/penguintechinc/project-template/services/go-backend/internal/metrics"
+	"github.com/penguintechinc/project-template/services/go-backend/internal/offload"
 	"github.com/penguintechinc/project-template/services/go-backend/internal/xdp"
 )
 
@@ -20,64 +25,246 @@ type HealthResponse struct {
 
 // StatusResponse is the response for the status endpoint.
 type StatusResponse struct {
-	Status      string            `json:"status"`
-	Service     string            `json:"service"`
-	Version     string            `json:"version"`
-	Timestamp   string            `json:"timestamp"`
-	Uptime      string            `json:"uptime"`
-	GoVersion   string            `json:"go_version"`
-	NumCPU      int               `json:"num_cpu"`
-	NumGoroutine int              `json:"num_goroutine"`
-	NUMA        *NUMAStatus       `json:"numa,omitempty"`
-	XDP         *XDPStatus        `json:"xdp,omitempty"`
-	MemoryPool  *MemoryPoolStatus `json:"memory_pool,omitempty"`
+	Status       string            `json:"status"`
+	Service      string            `json:"service"`
+	Version      string            `json:"version"`
+	Timestamp    string            `json:"timestamp"`
+	Uptime       string            `json:"uptime"`
+	GoVersion    string            `json:"go_version"`
+	NumCPU       int               `json:"num_cpu"`
+	NumGoroutine int               `json:"num_goroutine"`
+	NUMA         *NUMAStatus       `json:"numa,omitempty"`
+	XDP          *XDPStatus        `json:"xdp,omitempty"`
+	MemoryPool   *MemoryPoolStatus `json:"memory_pool,omitempty"`
+	Batch        *BatchStatus      `json:"batch,omitempty"`
 }
 
 // NUMAStatus represents NUMA topology status.
 type NUMAStatus struct {
-	Available   bool           `json:"available"`
-	NodeCount   int            `json:"node_count"`
-	CurrentNode int            `json:"current_node"`
-	MemoryMB    map[int]int64  `json:"memory_mb,omitempty"`
+	Available   bool          `json:"available"`
+	NodeCount   int           `json:"node_count"`
+	CurrentNode int           `json:"current_node"`
+	MemoryMB    map[int]int64 `json:"memory_mb,omitempty"`
 }
 
 // XDPStatus represents XDP availability status.
 type XDPStatus struct {
-	Supported bool   `json:"supported"`
-	Mode      string `json:"mode,omitempty"`
-	Interface string `json:"interface,omitempty"`
+	Supported     bool    `json:"supported"`
+	Mode          string  `json:"mode,omitempty"`
+	Interface     string  `json:"interface,omitempty"`
+	RxRingDepth   *uint32 `json:"rx_ring_depth,omitempty"`
+	TxRingDepth   *uint32 `json:"tx_ring_depth,omitempty"`
+	FillRingDepth *uint32 `json:"fill_ring_depth,omitempty"`
+	CompRingDepth *uint32 `json:"comp_ring_depth,omitempty"`
 }
 
 // MemoryPoolStatus represents memory pool status.
 type MemoryPoolStatus struct {
-	TotalSlots  int    `json:"total_slots"`
-	UsedSlots   int    `json:"used_slots"`
-	FreeSlots   int    `json:"free_slots"`
-	SlotSize    int    `json:"slot_size"`
-	TotalMemory int    `json:"total_memory_bytes"`
-	PeakUsage   int32  `json:"peak_usage"`
+	TotalSlots  int   `json:"total_slots"`
+	UsedSlots   int   `json:"used_slots"`
+	FreeSlots   int   `json:"free_slots"`
+	SlotSize    int   `json:"slot_size"`
+	TotalMemory int   `json:"total_memory_bytes"`
+	PeakUsage   int32 `json:"peak_usage"`
 }
 
+// BatchStatus represents the batched UDP I/O path's throughput counters
+// (see config.BatchEnabled, xdp.BatchConn). Present only when a batch
+// connection was configured.
+type BatchStatus struct {
+	PacketsSent     uint64  `json:"packets_sent"`
+	PacketsReceived uint64  `json:"packets_received"`
+	BatchesSent     uint64  `json:"batches_sent"`
+	BatchesReceived uint64  `json:"batches_received"`
+	SendErrors      uint64  `json:"send_errors"`
+	RecvErrors      uint64  `json:"recv_errors"`
+	AvgSendBatch    float64 `json:"avg_send_batch"`
+	AvgRecvBatch    float64 `json:"avg_recv_batch"`
+}
+
+// xdpReaderBatch is how many frames StartXDPReader pulls from the socket
+// per Poll call.
+const xdpReaderBatch = 32
+
+// batchReaderSize is how many packets StartBatchReader pulls from the
+// memory pool and hands to BatchConn.ReadBatch per call, mirroring
+// xdpReaderBatch's AF_XDP equivalent.
+const batchReaderSize = 32
+
 // Handlers holds all HTTP handlers and their dependencies.
 type Handlers struct {
-	startTime   time.Time
-	version     string
-	memoryPool  *memory.MemoryPool
-	xdpEnabled  bool
-	xdpMode     string
-	xdpIface    string
+	startTime  time.Time
+	version    string
+	memoryPool memory.Pool
+	xdpEnabled bool
+	xdpMode    string
+	xdpIface   string
+	relay      *offload.Relay
+	metrics    *metrics.Metrics
+
+	xdpSocket  *xdp.XDPSocket
+	processor  *xdp.PacketProcessor
+	stopReader chan struct{}
+
+	// lastRxDropped is the XDP_STATISTICS RxDropped value StartXDPReader
+	// last saw, since the kernel reports that field as a running total
+	// rather than a since-last-poll delta; only the difference is added
+	// to metrics.XDPPacketsDropped.
+	lastRxDropped uint64
+
+	batchConn       *xdp.BatchConn
+	stopBatchReader chan struct{}
 }
 
-// NewHandlers creates a new Handlers instance.
-func NewHandlers(version string, memPool *memory.MemoryPool, xdpEnabled bool, xdpMode, xdpIface string) *Handlers {
+// NewHandlers creates a new Handlers instance. xdpSocket may be nil, in
+// which case XDP-backed endpoints (PacketForward, the ring depth fields of
+// Status) fall back to the memory-pool-only simulation they used before
+// real AF_XDP I/O was wired in. batchConn may also be nil (batch UDP I/O
+// disabled, or no memory pool to back it); when set, StartBatchReader
+// drives it instead. m, if non-nil, is passed to xdp.WithMetrics (so the
+// packet processor's per-handler latency histogram and processed/dropped
+// counters are served from /metrics instead of sitting unused) and is
+// recorded against by StartXDPReader, PacketForward, and MemoryPoolStats.
+func NewHandlers(version string, memPool memory.Pool, xdpEnabled bool, xdpMode, xdpIface string, relay *offload.Relay, xdpSocket *xdp.XDPSocket, batchConn *xdp.BatchConn, m *metrics.Metrics) *Handlers {
+	var processorOpts []xdp.PacketProcessorOption
+	if m != nil {
+		processorOpts = append(processorOpts, xdp.WithMetrics(m.Registry()))
+	}
+
 	return &Handlers{
-		startTime:  time.Now(),
-		version:    version,
-		memoryPool: memPool,
-		xdpEnabled: xdpEnabled,
-		xdpMode:    xdpMode,
-		xdpIface:   xdpIface,
+		startTime:       time.Now(),
+		version:         version,
+		memoryPool:      memPool,
+		xdpEnabled:      xdpEnabled,
+		xdpMode:         xdpMode,
+		xdpIface:        xdpIface,
+		relay:           relay,
+		metrics:         m,
+		xdpSocket:       xdpSocket,
+		processor:       xdp.NewPacketProcessor(processorOpts...),
+		stopReader:      make(chan struct{}),
+		batchConn:       batchConn,
+		stopBatchReader: make(chan struct{}),
+	}
+}
+
+// AddXDPHandler registers h on the packet pipeline StartXDPReader drives.
+// It is not synchronized with the reader goroutine: PacketProcessor.AddHandler
+// appends to the handler slices without a lock, while the reader goroutine
+// ranges over them on every Poll batch, so a call after StartXDPReader is a
+// data race.
+func (h *Handlers) AddXDPHandler(handler xdp.PacketHandler) {
+	h.processor.AddHandler(handler)
+}
+
+// StartXDPReader starts the goroutine that polls xdpSocket and runs every
+// received frame through h's PacketProcessor. It's a no-op if no socket
+// was configured (XDP disabled, or running on a host that can't open
+// AF_XDP sockets).
+func (h *Handlers) StartXDPReader() {
+	if h.xdpSocket == nil {
+		return
+	}
+
+	go func() {
+		for {
+			select {
+			case <-h.stopReader:
+				return
+			default:
+			}
+
+			frames, err := h.xdpSocket.Poll(xdpReaderBatch)
+			if err != nil {
+				return
+			}
+			for _, frame := range frames {
+				start := time.Now()
+				h.processor.Process(frame)
+				if h.metrics != nil {
+					h.metrics.XDPPacketsReceived.Inc()
+					h.metrics.XDPBytesReceived.Add(float64(len(frame)))
+					h.metrics.RecordXDPProcessing(float64(time.Since(start).Nanoseconds()), "")
+				}
+			}
+
+			if h.metrics != nil {
+				if stats, err := h.xdpSocket.Stats(); err == nil && stats.RxDropped > h.lastRxDropped {
+					h.metrics.XDPPacketsDropped.Add(float64(stats.RxDropped - h.lastRxDropped))
+					h.lastRxDropped = stats.RxDropped
+				}
+			}
+		}
+	}()
+}
+
+// StopXDPReader stops the goroutine started by StartXDPReader.
+func (h *Handlers) StopXDPReader() {
+	if h.xdpSocket == nil {
+		return
+	}
+	close(h.stopReader)
+}
+
+// StartBatchReader starts the goroutine that draws buffers from the
+// memory pool, reads batches of packets off batchConn via recvmmsg(2) (or
+// the per-packet fallback on non-Linux), and runs each batch through h's
+// PacketProcessor the same way StartXDPReader does for AF_XDP frames.
+// It's a no-op if no batch connection was configured.
+func (h *Handlers) StartBatchReader() {
+	if h.batchConn == nil {
+		return
+	}
+
+	go func() {
+		bufs := make([][]byte, batchReaderSize)
+		sizes := make([]int, batchReaderSize)
+		slots := make([]int, batchReaderSize)
+
+		for {
+			select {
+			case <-h.stopBatchReader:
+				return
+			default:
+			}
+
+			n := 0
+			for n < batchReaderSize {
+				slot, buf, err := h.memoryPool.Acquire()
+				if err != nil {
+					break
+				}
+				slots[n] = slot
+				bufs[n] = buf
+				n++
+			}
+			if n == 0 {
+				continue
+			}
+
+			received, err := h.batchConn.ReadBatch(bufs[:n], sizes[:n])
+			if err != nil {
+				for i := 0; i < n; i++ {
+					_ = h.memoryPool.Release(slots[i])
+				}
+				return
+			}
+
+			h.processor.ProcessBatch(bufs, sizes, received)
+
+			for i := 0; i < n; i++ {
+				_ = h.memoryPool.Release(slots[i])
+			}
+		}
+	}()
+}
+
+// StopBatchReader stops the goroutine started by StartBatchReader.
+func (h *Handlers) StopBatchReader() {
+	if h.batchConn == nil {
+		return
 	}
+	close(h.stopBatchReader)
 }
 
 // HealthCheck handles GET /healthz
@@ -122,8 +309,16 @@ func (h *Handlers) Status(c *gin.Context) {
 		},
 	}
 
+	if h.xdpSocket != nil {
+		depths := h.xdpSocket.RingDepths()
+		response.XDP.RxRingDepth = &depths.RxRingDepth
+		response.XDP.TxRingDepth = &depths.TxRingDepth
+		response.XDP.FillRingDepth = &depths.FillRingDepth
+		response.XDP.CompRingDepth = &depths.CompRingDepth
+	}
+
 	if h.memoryPool != nil {
-		stats := h.memoryPool.Stats()
+		stats := h.memoryPool.AggregateStats()
 		response.MemoryPool = &MemoryPoolStatus{
 			TotalSlots:  stats.TotalSlots,
 			UsedSlots:   stats.UsedSlots,
@@ -132,6 +327,27 @@ func (h *Handlers) Status(c *gin.Context) {
 			TotalMemory: stats.TotalMemory,
 			PeakUsage:   stats.PeakUsage,
 		}
+		if h.metrics != nil {
+			h.metrics.UpdateMemoryPoolStats(h.memoryPool.PerNodeStats())
+		}
+	}
+
+	if h.batchConn != nil {
+		stats := h.batchConn.Stats()
+		response.Batch = &BatchStatus{
+			PacketsSent:     stats.PacketsSent,
+			PacketsReceived: stats.PacketsReceived,
+			BatchesSent:     stats.BatchesSent,
+			BatchesReceived: stats.BatchesReceived,
+			SendErrors:      stats.SendErrors,
+			RecvErrors:      stats.RecvErrors,
+			AvgSendBatch:    stats.AvgSendBatch,
+			AvgRecvBatch:    stats.AvgRecvBatch,
+		}
+	}
+
+	if h.metrics != nil {
+		h.metrics.UpdateNUMAStats(numaInfo.CurrentNode, numaInfo.Available, numaInfo.MemoryMB)
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -146,8 +362,18 @@ func (h *Handlers) Hello(c *gin.Context) {
 	})
 }
 
-// PacketForward handles POST /api/v1/packet/forward
-// This is an example endpoint demonstrating memory pool usage.
+// PacketForwardRequest is the optional request body for POST
+// /api/v1/packet/forward. Frame, if set, is transmitted as-is through the
+// AF_XDP socket; omitted it falls back to the memory-pool acquire/release
+// demo.
+type PacketForwardRequest struct {
+	Frame []byte `json:"frame,omitempty"`
+}
+
+// PacketForward handles POST /api/v1/packet/forward. With an AF_XDP
+// socket configured it transmits req.Frame (or, if omitted, the zeroed
+// contents of a freshly acquired buffer) through the real TX ring;
+// otherwise it demonstrates memory pool usage without any actual I/O.
 func (h *Handlers) PacketForward(c *gin.Context) {
 	if h.memoryPool == nil {
 		c.JSON(http.StatusServiceUnavailable, gin.H{
@@ -156,6 +382,9 @@ func (h *Handlers) PacketForward(c *gin.Context) {
 		return
 	}
 
+	var req PacketForwardRequest
+	_ = c.ShouldBindJSON(&req)
+
 	// Acquire a buffer from the pool
 	slotIdx, buffer, err := h.memoryPool.Acquire()
 	if err != nil {
@@ -165,9 +394,19 @@ func (h *Handlers) PacketForward(c *gin.Context) {
 		return
 	}
 
-	// Simulate packet processing
-	// In a real implementation, this would process actual packet data
-	_ = buffer
+	frame := req.Frame
+	if frame == nil {
+		frame = buffer
+	}
+
+	var transmitErr error
+	if h.xdpSocket != nil {
+		transmitErr = h.xdpSocket.Transmit([][]byte{frame})
+		if transmitErr == nil && h.metrics != nil {
+			h.metrics.XDPPacketsSent.Inc()
+			h.metrics.XDPBytesSent.Add(float64(len(frame)))
+		}
+	}
 
 	// Release the buffer back to the pool
 	if err := h.memoryPool.Release(slotIdx); err != nil {
@@ -177,10 +416,18 @@ func (h *Handlers) PacketForward(c *gin.Context) {
 		return
 	}
 
+	if transmitErr != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": fmt.Sprintf("failed to transmit frame: %v", transmitErr),
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"message":   "Packet processed successfully",
-		"slot_used": slotIdx,
-		"timestamp": time.Now().UTC().Format(time.RFC3339),
+		"message":     "Packet processed successfully",
+		"slot_used":   slotIdx,
+		"transmitted": h.xdpSocket != nil,
+		"timestamp":   time.Now().UTC().Format(time.RFC3339),
 	})
 }
 
@@ -193,18 +440,21 @@ func (h *Handlers) MemoryPoolStats(c *gin.Context) {
 		return
 	}
 
-	stats := h.memoryPool.Stats()
+	stats := h.memoryPool.AggregateStats()
+	if h.metrics != nil {
+		h.metrics.UpdateMemoryPoolStats(h.memoryPool.PerNodeStats())
+	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"total_slots":   stats.TotalSlots,
-		"used_slots":    stats.UsedSlots,
-		"free_slots":    stats.FreeSlots,
-		"slot_size":     stats.SlotSize,
-		"total_memory":  stats.TotalMemory,
-		"total_allocs":  stats.TotalAllocs,
-		"total_frees":   stats.TotalFrees,
-		"peak_usage":    stats.PeakUsage,
-		"utilization":   float64(stats.UsedSlots) / float64(stats.TotalSlots) * 100,
+		"total_slots":  stats.TotalSlots,
+		"used_slots":   stats.UsedSlots,
+		"free_slots":   stats.FreeSlots,
+		"slot_size":    stats.SlotSize,
+		"total_memory": stats.TotalMemory,
+		"total_allocs": stats.TotalAllocs,
+		"total_frees":  stats.TotalFrees,
+		"peak_usage":   stats.PeakUsage,
+		"utilization":  float64(stats.UsedSlots) / float64(stats.TotalSlots) * 100,
 	})
 }
 
@@ -220,3 +470,111 @@ func (h *Handlers) NUMAInfo(c *gin.Context) {
 		"memory_mb":     info.MemoryMB,
 	})
 }
+
+// RelayAllocationRequest is the request body for POST /api/v1/relay/allocations.
+type RelayAllocationRequest struct {
+	ID         uint32 `json:"id" binding:"required"`
+	ListenIP   string `json:"listen_ip" binding:"required"`
+	ListenPort int    `json:"listen_port" binding:"required"`
+	PeerIP     string `json:"peer_ip" binding:"required"`
+	PeerPort   int    `json:"peer_port" binding:"required"`
+}
+
+// RelayAddAllocation handles POST /api/v1/relay/allocations
+func (h *Handlers) RelayAddAllocation(c *gin.Context) {
+	if h.relay == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Relay offload not initialized",
+		})
+		return
+	}
+
+	var req RelayAllocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	listenIP := net.ParseIP(req.ListenIP)
+	peerIP := net.ParseIP(req.PeerIP)
+	if listenIP == nil || peerIP == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid listen_ip or peer_ip"})
+		return
+	}
+
+	listen := &net.UDPAddr{IP: listenIP, Port: req.ListenPort}
+	peer := &net.UDPAddr{IP: peerIP, Port: req.PeerPort}
+
+	if err := h.relay.AddAllocation(req.ID, listen, peer); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"id":          req.ID,
+		"listen_addr": listen.String(),
+		"peer_addr":   peer.String(),
+	})
+}
+
+// RelayRemoveAllocation handles DELETE /api/v1/relay/allocations/:id
+func (h *Handlers) RelayRemoveAllocation(c *gin.Context) {
+	if h.relay == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Relay offload not initialized",
+		})
+		return
+	}
+
+	id, err := parseAllocationID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.relay.RemoveAllocation(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"id": id})
+}
+
+// RelayAllocationStats handles GET /api/v1/relay/allocations/:id/stats
+func (h *Handlers) RelayAllocationStats(c *gin.Context) {
+	if h.relay == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Relay offload not initialized",
+		})
+		return
+	}
+
+	id, err := parseAllocationID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	stats, err := h.relay.Stats(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":          id,
+		"packets_in":  stats.PacketsIn,
+		"bytes_in":    stats.BytesIn,
+		"packets_out": stats.PacketsOut,
+		"bytes_out":   stats.BytesOut,
+	})
+}
+
+// parseAllocationID parses a relay allocation ID path parameter.
+func parseAllocationID(s string) (uint32, error) {
+	id, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid allocation id %q: %w", s, err)
+	}
+	return uint32(id), nil
+}
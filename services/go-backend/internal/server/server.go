@@ -4,25 +4,41 @@ package server
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
+	"runtime"
+	runtimemetrics "runtime/metrics"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sys/unix"
 
 	"github.com/penguintechinc/project-template/services/go-backend/internal/config"
+	"github.com/penguintechinc/project-template/services/go-backend/internal/grpcserver"
+	"github.com/penguintechinc/project-template/services/go-backend/internal/grpcsrv"
 	"github.com/penguintechinc/project-template/services/go-backend/internal/memory"
 	"github.com/penguintechinc/project-template/services/go-backend/internal/metrics"
+	"github.com/penguintechinc/project-template/services/go-backend/internal/offload"
+	"github.com/penguintechinc/project-template/services/go-backend/internal/xdp"
 )
 
 // Server represents the HTTP server.
 type Server struct {
-	config     *config.Config
-	router     *gin.Engine
-	httpServer *http.Server
-	handlers   *Handlers
-	metrics    *metrics.Metrics
-	memPool    *memory.MemoryPool
+	config            *config.Config
+	router            *gin.Engine
+	httpServer        *http.Server
+	grpcListener      *grpcserver.Listener
+	apiv1Srv          *grpcsrv.Server
+	handlers          *Handlers
+	metrics           *metrics.Metrics
+	memPool           memory.Pool
+	xdpProgram        *xdp.XDPProgram
+	xdpSocket         *xdp.XDPSocket
+	batchConn         *xdp.BatchConn
+	stopCgroupMonitor chan struct{}
 }
 
 // NewServer creates a new HTTP server instance.
@@ -41,11 +57,18 @@ func NewServer(cfg *config.Config) (*Server, error) {
 	// Add logging and metrics middleware
 	router.Use(loggingMiddleware())
 	router.Use(metricsMiddleware(m))
+	if cfg.ResourceAccountingEnabled {
+		router.Use(resourceAccountingMiddleware(m))
+	}
 
-	// Initialize memory pool if enabled
-	var memPool *memory.MemoryPool
+	// Initialize memory pool if enabled. With NUMA enabled this is a
+	// NUMAShardedPool (one node-local shard per NUMA node, routed through
+	// by Acquire) instead of the single-node MemoryPool, so the packet
+	// path actually gets the cross-socket-traffic avoidance NUMAEnabled
+	// implies; both satisfy memory.Pool, so nothing downstream (handlers,
+	// grpcsrv, grpcserver) needs to know which one it was handed.
+	var memPool memory.Pool
 	if cfg.MemoryPoolSize > 0 {
-		var err error
 		poolConfig := memory.PoolConfig{
 			NumSlots:     cfg.MemoryPoolSize,
 			SlotSize:     cfg.MemorySlotSize,
@@ -53,29 +76,122 @@ func NewServer(cfg *config.Config) (*Server, error) {
 			UseHugepages: cfg.HugepagesEnabled,
 			Preallocate:  cfg.MemoryPreallocate,
 		}
-		memPool, err = memory.NewMemoryPool(poolConfig)
+		if cfg.NUMAEnabled {
+			numaPool, err := memory.NewNUMAShardedPool(poolConfig)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create NUMA-sharded memory pool: %w", err)
+			}
+			memPool = numaPool
+		} else {
+			pool, err := memory.NewMemoryPool(poolConfig)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create memory pool: %w", err)
+			}
+			memPool = pool
+		}
+	}
+
+	// Load the XDP dispatcher, stand up the relay offload on top of its
+	// maps, and open a single AF_XDP socket on queue 0 for the Gin
+	// handlers' own RX/TX path if XDP is enabled. When it isn't, the relay
+	// endpoints return 503 (see Handlers.relay) and PacketForward falls back
+	// to its memory-pool-only path (see Handlers.xdpSocket).
+	var xdpProgram *xdp.XDPProgram
+	var relay *offload.Relay
+	var xdpSocket *xdp.XDPSocket
+	if cfg.XDPEnabled {
+		var err error
+		xdpProgram, err = xdp.LoadXDPProgram(xdp.XDPConfig{
+			InterfaceName: cfg.XDPInterface,
+			Mode:          xdp.ParseXDPMode(cfg.XDPMode),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to load XDP program: %w", err)
+		}
+		relay = offload.NewRelay(xdpProgram.RelayAllocations(), xdpProgram.RelayCounters())
+
+		xdpSocket, err = xdp.NewXDPSocket(xdp.DefaultSocketConfig(cfg.XDPInterface))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open AF_XDP socket: %w", err)
+		}
+		if err := registerHandlerSocket(xdpProgram, xdpSocket); err != nil {
+			return nil, err
+		}
+	}
+
+	// Open the batch UDP fast path (sendmmsg/recvmmsg) as an alternative
+	// to XDP for hosts without AF_XDP support. It needs a memory pool to
+	// back ReadBatch's packet buffers, so it's gated on one existing too.
+	var batchConn *xdp.BatchConn
+	if cfg.BatchEnabled && memPool != nil {
+		udpAddr, err := net.ResolveUDPAddr("udp", cfg.BatchListenAddr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve batch listen address: %w", err)
+		}
+		conn, err := net.ListenUDP("udp", udpAddr)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create memory pool: %w", err)
+			return nil, fmt.Errorf("failed to open batch UDP listener: %w", err)
 		}
+		batchConn = xdp.NewBatchConn(conn, memory.NewBufferPool(memPool))
 	}
 
 	// Initialize handlers
-	handlers := NewHandlers("1.0.0", memPool, cfg.XDPEnabled, cfg.XDPMode, cfg.XDPInterface)
+	handlers := NewHandlers("1.0.0", memPool, cfg.XDPEnabled, cfg.XDPMode, cfg.XDPInterface, relay, xdpSocket, batchConn, m)
+	handlers.StartXDPReader()
+	handlers.StartBatchReader()
+
+	// The gRPC control plane manages its own XDP program/relay lifecycle
+	// (see grpcserver.Server.LoadXDPProgram), independent of cfg.XDPEnabled:
+	// it's meant for a sidecar that wants to load/detach the program
+	// itself rather than rely on the config-driven default above. ApiV1,
+	// registered on the same *grpc.Server, just mirrors the Gin handlers'
+	// own memory pool and AF_XDP socket.
+	apiv1Srv := grpcsrv.NewServer("1.0.0", memPool, cfg.XDPMode, cfg.XDPInterface, xdpSocket)
+	grpcListener := grpcserver.NewListener(memPool, m, apiv1Srv)
 
 	server := &Server{
-		config:   cfg,
-		router:   router,
-		handlers: handlers,
-		metrics:  m,
-		memPool:  memPool,
+		config:            cfg,
+		router:            router,
+		grpcListener:      grpcListener,
+		apiv1Srv:          apiv1Srv,
+		handlers:          handlers,
+		metrics:           m,
+		memPool:           memPool,
+		xdpProgram:        xdpProgram,
+		xdpSocket:         xdpSocket,
+		batchConn:         batchConn,
+		stopCgroupMonitor: make(chan struct{}),
 	}
 
 	// Register routes
 	server.registerRoutes()
 
+	// Refresh cgroup v2 limit/pressure metrics periodically so operators
+	// can see container memory/CPU headroom without shelling in.
+	go monitorCgroupLimits(m, server.stopCgroupMonitor)
+
 	return server, nil
 }
 
+// registerHandlerSocket registers sock's fd in program's xsks_map for
+// queue 0 and marks that queue redirected, the same two steps
+// xdp.SocketPool performs per queue, so packets the dispatcher steers to
+// queue 0 land on the single socket the Gin handlers read from.
+func registerHandlerSocket(program *xdp.XDPProgram, sock *xdp.XDPSocket) error {
+	queueKey := uint32(0)
+	fd := uint32(sock.FileDescriptor())
+	if err := program.XSKMap().Put(&queueKey, &fd); err != nil {
+		return fmt.Errorf("failed to register AF_XDP socket: %w", err)
+	}
+
+	redirect := uint8(1)
+	if err := program.RedirectFlows().Put(&queueKey, &redirect); err != nil {
+		return fmt.Errorf("failed to mark queue 0 redirected: %w", err)
+	}
+
+	return nil
+}
+
 // registerRoutes sets up all HTTP routes.
 func (s *Server) registerRoutes() {
 	// Health check endpoints
@@ -83,7 +199,7 @@ func (s *Server) registerRoutes() {
 	s.router.GET("/readyz", s.handlers.ReadinessCheck)
 
 	// Metrics endpoint
-	s.router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	s.router.GET("/metrics", gin.WrapH(metricsHandler(s.metrics.Registry())))
 
 	// API v1 routes
 	v1 := s.router.Group("/api/v1")
@@ -97,7 +213,17 @@ func (s *Server) registerRoutes() {
 
 		// NUMA information
 		v1.GET("/numa/info", s.handlers.NUMAInfo)
+
+		// Relay offload endpoints
+		v1.POST("/relay/allocations", s.handlers.RelayAddAllocation)
+		v1.DELETE("/relay/allocations/:id", s.handlers.RelayRemoveAllocation)
+		v1.GET("/relay/allocations/:id/stats", s.handlers.RelayAllocationStats)
 	}
+
+	// grpc-gateway-style reverse proxy: re-exposes the ApiV1 gRPC service
+	// registered on s.grpcListener over plain JSON, for callers that want
+	// the gRPC-backed handlers without a gRPC client.
+	grpcsrv.NewGatewayRoutes(s.router.Group("/grpc/v1"), s.apiv1Srv)
 }
 
 // Start starts the HTTP server.
@@ -115,13 +241,63 @@ func (s *Server) Start() error {
 	return s.httpServer.ListenAndServe()
 }
 
+// StartAll starts the gRPC control plane listener in the background, then
+// starts the HTTP server in the foreground the same way Start does. Use
+// this instead of Start when the gRPC control plane
+// (internal/grpcserver) should be reachable alongside the Gin API.
+func (s *Server) StartAll() error {
+	grpcAddr := fmt.Sprintf("%s:%d", s.config.ServerHost, s.config.GRPCPort)
+
+	grpcErrCh := make(chan error, 1)
+	go func() {
+		grpcErrCh <- s.grpcListener.ListenAndServe(grpcAddr)
+	}()
+
+	httpErr := s.Start()
+
+	select {
+	case grpcErr := <-grpcErrCh:
+		if httpErr != nil {
+			return httpErr
+		}
+		return grpcErr
+	default:
+		return httpErr
+	}
+}
+
 // Shutdown gracefully shuts down the server.
 func (s *Server) Shutdown(ctx context.Context) error {
+	close(s.stopCgroupMonitor)
+
+	s.grpcListener.GracefulStop()
+
+	s.handlers.StopXDPReader()
+	if s.xdpSocket != nil {
+		if err := s.xdpSocket.Close(); err != nil {
+			return fmt.Errorf("failed to close AF_XDP socket: %w", err)
+		}
+	}
+
+	s.handlers.StopBatchReader()
+	if s.batchConn != nil {
+		if err := s.batchConn.Close(); err != nil {
+			return fmt.Errorf("failed to close batch UDP connection: %w", err)
+		}
+	}
+
 	// Close memory pool
 	if s.memPool != nil {
 		s.memPool.Close()
 	}
 
+	// Detach the XDP program
+	if s.xdpProgram != nil {
+		if err := s.xdpProgram.Detach(); err != nil {
+			return fmt.Errorf("failed to detach XDP program: %w", err)
+		}
+	}
+
 	// Shutdown HTTP server
 	if s.httpServer != nil {
 		return s.httpServer.Shutdown(ctx)
@@ -130,6 +306,21 @@ func (s *Server) Shutdown(ctx context.Context) error {
 	return nil
 }
 
+// metricsHandler serves /metrics, negotiating the protobuf format that
+// carries native histograms when the caller passes ?format=native. Plain
+// scrapes are untouched and keep getting the default negotiated format
+// (text, unless the scraper's Accept header already asks for protobuf).
+func metricsHandler(registry *prometheus.Registry) http.Handler {
+	promHandler := promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("format") == "native" {
+			r.Header.Set("Accept", "application/vnd.google.protobuf; proto=io.prometheus.client.MetricFamily; encoding=delimited;q=0.7,text/plain;version=0.0.4;q=0.3")
+		}
+		promHandler.ServeHTTP(w, r)
+	})
+}
+
 // loggingMiddleware provides request logging.
 func loggingMiddleware() gin.HandlerFunc {
 	return gin.LoggerWithConfig(gin.LoggerConfig{
@@ -150,6 +341,91 @@ func metricsMiddleware(m *metrics.Metrics) gin.HandlerFunc {
 		duration := time.Since(start).Seconds()
 		status := fmt.Sprintf("%d", c.Writer.Status())
 
-		m.RecordHTTPRequest(c.Request.Method, c.FullPath(), status, duration)
+		m.RecordHTTPRequest(c.Request.Method, c.FullPath(), status, duration, traceIDFromRequest(c.Request))
+	}
+}
+
+// allocBytesSample is the runtime/metrics counter for cumulative bytes
+// allocated by the heap; cheaper to read than a full ReadMemStats.
+const allocBytesSample = "/gc/heap/allocs:bytes"
+
+// resourceAccountingMiddleware measures per-request CPU time and heap
+// allocations and records them as histograms. It locks the handling
+// goroutine to its OS thread for the request's duration so
+// getrusage(RUSAGE_THREAD) reflects only this request's CPU usage; that
+// cost is why it's gated behind config.ResourceAccountingEnabled.
+func resourceAccountingMiddleware(m *metrics.Metrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+
+		samples := []runtimemetrics.Sample{{Name: allocBytesSample}}
+
+		runtimemetrics.Read(samples)
+		allocBefore := samples[0].Value.Uint64()
+
+		var before unix.Rusage
+		haveBefore := unix.Getrusage(unix.RUSAGE_THREAD, &before) == nil
+
+		c.Next()
+
+		runtimemetrics.Read(samples)
+		allocAfter := samples[0].Value.Uint64()
+
+		var cpuSeconds float64
+		if haveBefore {
+			var after unix.Rusage
+			if unix.Getrusage(unix.RUSAGE_THREAD, &after) == nil {
+				cpuSeconds = rusageCPUSeconds(after) - rusageCPUSeconds(before)
+			}
+		}
+
+		m.RecordHTTPRequestResources(c.Request.Method, c.FullPath(), cpuSeconds, float64(allocAfter-allocBefore))
+	}
+}
+
+// rusageCPUSeconds returns the combined user+sys CPU time recorded in an
+// rusage struct, in seconds.
+func rusageCPUSeconds(r unix.Rusage) float64 {
+	user := time.Duration(r.Utime.Sec)*time.Second + time.Duration(r.Utime.Usec)*time.Microsecond
+	sys := time.Duration(r.Stime.Sec)*time.Second + time.Duration(r.Stime.Usec)*time.Microsecond
+	return (user + sys).Seconds()
+}
+
+// monitorCgroupLimits refreshes cgroup v2 resource metrics every 10s until
+// stopCh is closed.
+func monitorCgroupLimits(m *metrics.Metrics, stopCh <-chan struct{}) {
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+
+	refreshCgroupMetrics(m)
+
+	for {
+		select {
+		case <-ticker.C:
+			refreshCgroupMetrics(m)
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+// refreshCgroupMetrics reads the current cgroup v2 limits and pressure and
+// publishes them. It's a no-op (not an error) on hosts without cgroup v2.
+func refreshCgroupMetrics(m *metrics.Metrics) {
+	limits, err := memory.DetectCgroupLimits()
+	if err != nil || !limits.Available {
+		return
+	}
+	m.UpdateCgroupStats(limits)
+}
+
+// traceIDFromRequest returns the hex-encoded trace ID of the OpenTelemetry
+// span active on the request's context, or "" if none is present.
+func traceIDFromRequest(r *http.Request) string {
+	spanCtx := trace.SpanContextFromContext(r.Context())
+	if !spanCtx.IsValid() {
+		return ""
 	}
+	return spanCtx.TraceID().String()
 }
@@ -0,0 +1,86 @@
+package xdp
+
+import (
+	"net"
+	"sync/atomic"
+
+	"github.com/penguintechinc/project-template/services/go-backend/internal/memory"
+)
+
+// BatchStats tracks the throughput of a BatchConn. All fields are updated
+// with atomic instructions so BatchConn.Stats can be called concurrently with
+// ReadBatch/WriteBatch on the hot path.
+type BatchStats struct {
+	packetsSent     atomic.Uint64
+	packetsReceived atomic.Uint64
+	batchesSent     atomic.Uint64
+	batchesReceived atomic.Uint64
+	sendErrors      atomic.Uint64
+	recvErrors      atomic.Uint64
+}
+
+// BatchStatsSnapshot is a point-in-time copy of BatchStats suitable for
+// JSON/proto serialization (e.g. from MemoryPoolStats or a sibling
+// endpoint).
+type BatchStatsSnapshot struct {
+	PacketsSent     uint64  `json:"packets_sent"`
+	PacketsReceived uint64  `json:"packets_received"`
+	BatchesSent     uint64  `json:"batches_sent"`
+	BatchesReceived uint64  `json:"batches_received"`
+	SendErrors      uint64  `json:"send_errors"`
+	RecvErrors      uint64  `json:"recv_errors"`
+	AvgSendBatch    float64 `json:"avg_send_batch"`
+	AvgRecvBatch    float64 `json:"avg_recv_batch"`
+}
+
+// snapshot builds a BatchStatsSnapshot from s.
+func (s *BatchStats) snapshot() BatchStatsSnapshot {
+	sent := s.packetsSent.Load()
+	received := s.packetsReceived.Load()
+	sentBatches := s.batchesSent.Load()
+	receivedBatches := s.batchesReceived.Load()
+
+	snap := BatchStatsSnapshot{
+		PacketsSent:     sent,
+		PacketsReceived: received,
+		BatchesSent:     sentBatches,
+		BatchesReceived: receivedBatches,
+		SendErrors:      s.sendErrors.Load(),
+		RecvErrors:      s.recvErrors.Load(),
+	}
+	if sentBatches > 0 {
+		snap.AvgSendBatch = float64(sent) / float64(sentBatches)
+	}
+	if receivedBatches > 0 {
+		snap.AvgRecvBatch = float64(received) / float64(receivedBatches)
+	}
+	return snap
+}
+
+// BatchConn moves multiple UDP packets per syscall (sendmmsg/recvmmsg on
+// Linux; see batch_linux.go and batch_other.go), so a pipeline built on it
+// can be driven at line rate on hosts without AF_XDP support. Buffers for
+// ReadBatch are expected to come from bufPool, matching the zero-allocation
+// convention XDPSocket already follows for its UMEM frames.
+type BatchConn struct {
+	conn    *net.UDPConn
+	bufPool *memory.BufferPool
+	stats   BatchStats
+}
+
+// NewBatchConn wraps conn for batched I/O. bufPool is stored on the
+// BatchConn but not used by it: BatchConn never calls Get/Put on bufPool
+// and has no accessor that returns it.
+func NewBatchConn(conn *net.UDPConn, bufPool *memory.BufferPool) *BatchConn {
+	return &BatchConn{conn: conn, bufPool: bufPool}
+}
+
+// Stats returns a snapshot of the connection's batch throughput counters.
+func (b *BatchConn) Stats() BatchStatsSnapshot {
+	return b.stats.snapshot()
+}
+
+// Close closes the underlying UDP socket.
+func (b *BatchConn) Close() error {
+	return b.conn.Close()
+}
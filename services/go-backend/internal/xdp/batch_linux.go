@@ -0,0 +1,191 @@
+//go:build linux
+
+package xdp
+
+import (
+	"net/netip"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// mmsghdr mirrors the kernel's struct mmsghdr (a Msghdr plus the length
+// the kernel filled in/consumed for that message). golang.org/x/sys/unix
+// has no Mmsghdr type or Recvmmsg/Sendmmsg wrappers, so — the same way
+// setsockopt/getsockopt in socket.go reach for raw Syscall6 where unix
+// has no typed wrapper — recvmmsg(2)/sendmmsg(2) are invoked directly via
+// SYS_RECVMMSG/SYS_SENDMMSG below. The trailing 4 bytes pad the struct to
+// Msghdr's 8-byte alignment, matching the C ABI for an array of these.
+type mmsghdr struct {
+	Hdr unix.Msghdr
+	Len uint32
+	_   [4]byte
+}
+
+// recvmmsg issues a raw recvmmsg(2) call with no timeout (msgs's fd is
+// non-blocking, as all Go net sockets are; the caller retries on EAGAIN
+// via the runtime netpoller). Returns the number of messages received.
+func recvmmsg(fd int, msgs []mmsghdr, flags int) (int, error) {
+	if len(msgs) == 0 {
+		return 0, nil
+	}
+	r1, _, errno := unix.Syscall6(unix.SYS_RECVMMSG, uintptr(fd), uintptr(unsafe.Pointer(&msgs[0])), uintptr(len(msgs)), uintptr(flags), 0, 0)
+	if errno != 0 {
+		return 0, errno
+	}
+	return int(r1), nil
+}
+
+// sendmmsg issues a raw sendmmsg(2) call; see recvmmsg. Returns the
+// number of messages sent.
+func sendmmsg(fd int, msgs []mmsghdr, flags int) (int, error) {
+	if len(msgs) == 0 {
+		return 0, nil
+	}
+	r1, _, errno := unix.Syscall6(unix.SYS_SENDMMSG, uintptr(fd), uintptr(unsafe.Pointer(&msgs[0])), uintptr(len(msgs)), uintptr(flags), 0, 0)
+	if errno != 0 {
+		return 0, errno
+	}
+	return int(r1), nil
+}
+
+// rawSockaddrInet is large enough to hold either a RawSockaddrInet4 or a
+// RawSockaddrInet6, the way the kernel's sockaddr_storage does, so one
+// mmsghdr.Hdr.Name slot can carry addresses of either family.
+type rawSockaddrInet struct {
+	_ [unix.SizeofSockaddrInet6]byte
+}
+
+// htons converts a host-byte-order port into the network byte order the
+// raw sockaddr structs expect; unlike Sockaddr{Inet4,Inet6}, Msghdr.Name
+// is handed to the kernel as-is with no byte-swapping done for us.
+func htons(port uint16) uint16 {
+	return (port << 8) | (port >> 8)
+}
+
+// fillSockaddr encodes ap into raw, returning its size for Msghdr.Namelen.
+func fillSockaddr(raw *rawSockaddrInet, ap netip.AddrPort) uint32 {
+	addr := ap.Addr()
+	if addr.Is4() || addr.Is4In6() {
+		sa := (*unix.RawSockaddrInet4)(unsafe.Pointer(raw))
+		*sa = unix.RawSockaddrInet4{}
+		sa.Family = unix.AF_INET
+		sa.Port = htons(ap.Port())
+		a4 := addr.As4()
+		sa.Addr = a4
+		return uint32(unix.SizeofSockaddrInet4)
+	}
+
+	sa := (*unix.RawSockaddrInet6)(unsafe.Pointer(raw))
+	*sa = unix.RawSockaddrInet6{}
+	sa.Family = unix.AF_INET6
+	sa.Port = htons(ap.Port())
+	sa.Addr = addr.As16()
+	return uint32(unix.SizeofSockaddrInet6)
+}
+
+// ReadBatch fills bufs with up to len(bufs) packets read in a single
+// recvmmsg(2) call, storing each packet's length in the matching slot of
+// sizes, and returns how many were received. bufs and sizes must have
+// equal, non-zero length; bufs' slices should come from a memory.BufferPool
+// so no per-packet allocation occurs.
+func (b *BatchConn) ReadBatch(bufs [][]byte, sizes []int) (int, error) {
+	n := len(bufs)
+	if n == 0 {
+		return 0, nil
+	}
+
+	iovecs := make([]unix.Iovec, n)
+	msgs := make([]mmsghdr, n)
+	for i := range bufs {
+		if len(bufs[i]) > 0 {
+			iovecs[i].Base = &bufs[i][0]
+		}
+		iovecs[i].SetLen(len(bufs[i]))
+		msgs[i].Hdr.Iov = &iovecs[i]
+		msgs[i].Hdr.SetIovlen(1)
+	}
+
+	rawConn, err := b.conn.SyscallConn()
+	if err != nil {
+		b.stats.recvErrors.Add(1)
+		return 0, err
+	}
+
+	var received int
+	var sysErr error
+	ctrlErr := rawConn.Read(func(fd uintptr) bool {
+		received, sysErr = recvmmsg(int(fd), msgs, 0)
+		if sysErr == unix.EAGAIN {
+			return false
+		}
+		return true
+	})
+	if ctrlErr != nil {
+		sysErr = ctrlErr
+	}
+	if sysErr != nil {
+		b.stats.recvErrors.Add(1)
+		return 0, sysErr
+	}
+
+	for i := 0; i < received; i++ {
+		sizes[i] = int(msgs[i].Len)
+	}
+	b.stats.packetsReceived.Add(uint64(received))
+	b.stats.batchesReceived.Add(1)
+	return received, nil
+}
+
+// WriteBatch sends len(pkts) packets in a single sendmmsg(2) call, one to
+// each matching address in addrs, and returns how many were accepted by
+// the kernel. pkts and addrs must have equal length.
+func (b *BatchConn) WriteBatch(pkts [][]byte, addrs []netip.AddrPort) (int, error) {
+	n := len(pkts)
+	if n == 0 {
+		return 0, nil
+	}
+
+	iovecs := make([]unix.Iovec, n)
+	names := make([]rawSockaddrInet, n)
+	msgs := make([]mmsghdr, n)
+	for i := range pkts {
+		if len(pkts[i]) > 0 {
+			iovecs[i].Base = &pkts[i][0]
+		}
+		iovecs[i].SetLen(len(pkts[i]))
+
+		nameLen := fillSockaddr(&names[i], addrs[i])
+		msgs[i].Hdr.Name = (*byte)(unsafe.Pointer(&names[i]))
+		msgs[i].Hdr.Namelen = nameLen
+		msgs[i].Hdr.Iov = &iovecs[i]
+		msgs[i].Hdr.SetIovlen(1)
+	}
+
+	rawConn, err := b.conn.SyscallConn()
+	if err != nil {
+		b.stats.sendErrors.Add(1)
+		return 0, err
+	}
+
+	var sent int
+	var sysErr error
+	ctrlErr := rawConn.Write(func(fd uintptr) bool {
+		sent, sysErr = sendmmsg(int(fd), msgs, 0)
+		if sysErr == unix.EAGAIN {
+			return false
+		}
+		return true
+	})
+	if ctrlErr != nil {
+		sysErr = ctrlErr
+	}
+	if sysErr != nil {
+		b.stats.sendErrors.Add(1)
+		return sent, sysErr
+	}
+
+	b.stats.packetsSent.Add(uint64(sent))
+	b.stats.batchesSent.Add(1)
+	return sent, nil
+}
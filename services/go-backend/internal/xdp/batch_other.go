@@ -0,0 +1,54 @@
+//go:build !linux
+
+package xdp
+
+import "net/netip"
+
+// ReadBatch reads up to len(bufs) packets, one per ReadFromUDPAddrPort
+// call, storing each packet's length in the matching slot of sizes, and
+// returns how many were received. Non-Linux builds don't get the
+// recvmmsg(2) fast path (see batch_linux.go) — this loop is already the
+// best this platform offers.
+func (b *BatchConn) ReadBatch(bufs [][]byte, sizes []int) (int, error) {
+	received := 0
+	for i := range bufs {
+		n, _, err := b.conn.ReadFromUDPAddrPort(bufs[i])
+		if err != nil {
+			if received > 0 {
+				break
+			}
+			b.stats.recvErrors.Add(1)
+			return 0, err
+		}
+		sizes[i] = n
+		received++
+	}
+	if received > 0 {
+		b.stats.packetsReceived.Add(uint64(received))
+		b.stats.batchesReceived.Add(1)
+	}
+	return received, nil
+}
+
+// WriteBatch sends len(pkts) packets, one per WriteToUDPAddrPort call to
+// its matching address in addrs, and returns how many were sent. Non-Linux
+// builds don't get the sendmmsg(2) fast path (see batch_linux.go) — this
+// loop is already the best this platform offers.
+func (b *BatchConn) WriteBatch(pkts [][]byte, addrs []netip.AddrPort) (int, error) {
+	sent := 0
+	for i, pkt := range pkts {
+		if _, err := b.conn.WriteToUDPAddrPort(pkt, addrs[i]); err != nil {
+			if sent > 0 {
+				break
+			}
+			b.stats.sendErrors.Add(1)
+			return 0, err
+		}
+		sent++
+	}
+	if sent > 0 {
+		b.stats.packetsSent.Add(uint64(sent))
+		b.stats.batchesSent.Add(1)
+	}
+	return sent, nil
+}
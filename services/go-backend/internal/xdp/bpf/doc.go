@@ -0,0 +1,44 @@
+// Package bpf holds the eBPF C sources attached to a NIC by
+// xdp.LoadXDPProgram, plus the Go bindings bpf2go generates from them
+// (DispatcherObjects, LoadDispatcherObjects, ...).
+//
+// dispatcher.c implements all of the following in one XDP program so only
+// a single attach point is needed per queue:
+//   - a per-CPU packet counter
+//   - a source-IPv4 allow/deny list
+//   - an in-place rewrite, sent back out with XDP_TX, of UDP packets
+//     addressed to a relay allocation's listen port (see internal/offload)
+//   - a redirect into an XSKMAP for queues an AF_XDP socket has registered
+//     itself against, or into a CPUMAP when fanout_mode selects it
+//
+// To regenerate the bindings after editing dispatcher.c, vendor the
+// libbpf headers it needs and run bpf2go:
+//
+//	./fetch-headers.sh
+//	go generate ./internal/xdp/bpf
+//
+// headers/ is not committed: it's only needed to regenerate, not to
+// build this package, and following pion/turn's XDP offload build we'd
+// rather re-fetch the headers than let a vendored copy drift from the
+// libbpf version bpf2go is actually run against.
+//
+// dispatcher_bpfel.o as checked in is a placeholder (its contents are
+// the literal text of the go:generate line below, not compiled bytecode)
+// until someone with a clang/libbpf toolchain runs that line for real.
+// xdp.LoadXDPProgram calls IsDispatcherBuilt before trying to load it, so
+// cfg.XDPEnabled fails with a clear "not built" error rather than a
+// confusing ELF-parse error until that's done.
+package bpf
+
+//go:generate go run github.com/cilium/ebpf/cmd/bpf2go -cc clang -cflags "-O2 -g -Wall -Werror -I./headers" -target bpfel Dispatcher dispatcher.c
+
+// IsDispatcherBuilt reports whether dispatcher_bpfel.o is a real compiled
+// eBPF object rather than the placeholder text checked in until
+// go:generate has been run with a clang/libbpf toolchain available (see
+// the package doc above). LoadDispatcherObjects on the placeholder fails
+// with a confusing ELF-parse error, so callers should check this first.
+func IsDispatcherBuilt() bool {
+	return len(_DispatcherBytes) >= 4 &&
+		_DispatcherBytes[0] == 0x7f && _DispatcherBytes[1] == 'E' &&
+		_DispatcherBytes[2] == 'L' && _DispatcherBytes[3] == 'F'
+}
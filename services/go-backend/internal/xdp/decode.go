@@ -0,0 +1,479 @@
+package xdp
+
+import (
+	"encoding/binary"
+	"net"
+
+	"github.com/penguintechinc/project-template/services/go-backend/internal/cpu"
+)
+
+// IP Protocol / IPv6 Next Header constants not already covered by
+// packet.go's IPProto* block.
+const (
+	IPProtoICMPv6 = 58
+)
+
+// VLAN, ARP and ICMPv6 header sizes.
+const (
+	VLANHeaderSize   = 4
+	ARPHeaderSize    = 28 // Ethernet/IPv4 ARP only
+	ICMPHeaderSize   = 8
+	ICMPv6HeaderSize = 8
+	NDOptionSize     = 8 // one source/target link-layer address option
+)
+
+// ICMP (v4) type constants.
+const (
+	ICMPTypeEchoReply   = 0
+	ICMPTypeEchoRequest = 8
+)
+
+// ICMPv6 type constants.
+const (
+	ICMPv6TypeEchoRequest           = 128
+	ICMPv6TypeEchoReply             = 129
+	ICMPv6TypeNeighborSolicitation  = 135
+	ICMPv6TypeNeighborAdvertisement = 136
+)
+
+// ARP opcode and hardware/protocol type constants (Ethernet/IPv4 only).
+const (
+	ARPHardwareTypeEthernet = 1
+	ARPProtocolTypeIPv4     = EtherTypeIPv4
+	ARPOpRequest            = 1
+	ARPOpReply              = 2
+)
+
+// VLANHeader represents an 802.1Q tag: a 2-byte TCI (priority, DEI, VLAN
+// ID) followed by the EtherType of the frame it's wrapping.
+type VLANHeader struct {
+	PriorityCodePoint uint8
+	DropEligible      bool
+	VLANID            uint16
+	EtherType         uint16
+}
+
+// ParseVLANHeader parses an 802.1Q tag from a byte slice. data should
+// start right after the EtherTypeVLAN field of the Ethernet header.
+func ParseVLANHeader(data []byte) (*VLANHeader, error) {
+	if len(data) < VLANHeaderSize {
+		return nil, ErrPacketTooShort
+	}
+	tci := binary.BigEndian.Uint16(data[0:2])
+	return &VLANHeader{
+		PriorityCodePoint: uint8(tci >> 13),
+		DropEligible:      tci&0x1000 != 0,
+		VLANID:            tci & 0x0FFF,
+		EtherType:         binary.BigEndian.Uint16(data[2:4]),
+	}, nil
+}
+
+// Serialize writes the VLAN tag to a byte slice.
+func (h *VLANHeader) Serialize(data []byte) error {
+	if len(data) < VLANHeaderSize {
+		return ErrBufferTooSmall
+	}
+	tci := uint16(h.PriorityCodePoint)<<13 | h.VLANID
+	if h.DropEligible {
+		tci |= 0x1000
+	}
+	binary.BigEndian.PutUint16(data[0:2], tci)
+	binary.BigEndian.PutUint16(data[2:4], h.EtherType)
+	return nil
+}
+
+// IPv6Header represents an IPv6 packet header (no extension headers).
+type IPv6Header struct {
+	Version      uint8
+	TrafficClass uint8
+	FlowLabel    uint32
+	PayloadLen   uint16
+	NextHeader   uint8
+	HopLimit     uint8
+	SrcIP        net.IP
+	DstIP        net.IP
+}
+
+// ParseIPv6Header parses a fixed 40-byte IPv6 header from a byte slice.
+func ParseIPv6Header(data []byte) (*IPv6Header, error) {
+	if len(data) < IPv6HeaderSize {
+		return nil, ErrPacketTooShort
+	}
+
+	versionTrafficFlow := binary.BigEndian.Uint32(data[0:4])
+	version := uint8(versionTrafficFlow >> 28)
+	if version != 6 {
+		return nil, ErrInvalidPacket
+	}
+
+	return &IPv6Header{
+		Version:      version,
+		TrafficClass: uint8(versionTrafficFlow >> 20),
+		FlowLabel:    versionTrafficFlow & 0x000FFFFF,
+		PayloadLen:   binary.BigEndian.Uint16(data[4:6]),
+		NextHeader:   data[6],
+		HopLimit:     data[7],
+		SrcIP:        net.IP(data[8:24]),
+		DstIP:        net.IP(data[24:40]),
+	}, nil
+}
+
+// Serialize writes the IPv6 header to a byte slice.
+func (h *IPv6Header) Serialize(data []byte) error {
+	if len(data) < IPv6HeaderSize {
+		return ErrBufferTooSmall
+	}
+
+	versionTrafficFlow := uint32(h.Version)<<28 | uint32(h.TrafficClass)<<20 | h.FlowLabel&0x000FFFFF
+	binary.BigEndian.PutUint32(data[0:4], versionTrafficFlow)
+	binary.BigEndian.PutUint16(data[4:6], h.PayloadLen)
+	data[6] = h.NextHeader
+	data[7] = h.HopLimit
+	copy(data[8:24], h.SrcIP.To16())
+	copy(data[24:40], h.DstIP.To16())
+	return nil
+}
+
+// ICMPHeader represents an ICMPv4 header (type, code, checksum, and the
+// 4-byte rest-of-header field, uninterpreted since its meaning depends on
+// Type).
+type ICMPHeader struct {
+	Type         uint8
+	Code         uint8
+	Checksum     uint16
+	RestOfHeader uint32
+}
+
+// ParseICMPHeader parses an ICMPv4 header from a byte slice.
+func ParseICMPHeader(data []byte) (*ICMPHeader, error) {
+	if len(data) < ICMPHeaderSize {
+		return nil, ErrPacketTooShort
+	}
+	return &ICMPHeader{
+		Type:         data[0],
+		Code:         data[1],
+		Checksum:     binary.BigEndian.Uint16(data[2:4]),
+		RestOfHeader: binary.BigEndian.Uint32(data[4:8]),
+	}, nil
+}
+
+// Serialize writes the ICMPv4 header (and recomputes its checksum over
+// data, which must already hold the full ICMP message) to a byte slice.
+func (h *ICMPHeader) Serialize(data []byte) error {
+	if len(data) < ICMPHeaderSize {
+		return ErrBufferTooSmall
+	}
+	data[0] = h.Type
+	data[1] = h.Code
+	binary.BigEndian.PutUint16(data[2:4], 0)
+	binary.BigEndian.PutUint32(data[4:8], h.RestOfHeader)
+	h.Checksum = cpu.Checksum(data)
+	binary.BigEndian.PutUint16(data[2:4], h.Checksum)
+	return nil
+}
+
+// ICMPv6Header represents an ICMPv6 header. For Neighbor
+// Solicitation/Advertisement (Type 135/136), TargetAddress and Flags (NA
+// only) carry the rest of the message; the trailing source/target
+// link-layer address option, if present, is returned separately by
+// ParseICMPv6Header as LinkLayerAddr.
+type ICMPv6Header struct {
+	Type          uint8
+	Code          uint8
+	Checksum      uint16
+	Flags         uint32 // NA only: R/S/O bits in the top 3 bits
+	TargetAddress net.IP // set for NS/NA only
+	LinkLayerAddr net.HardwareAddr
+}
+
+// ParseICMPv6Header parses an ICMPv6 header from a byte slice, decoding
+// the Neighbor Solicitation/Advertisement body and trailing link-layer
+// address option when Type is 135 or 136.
+func ParseICMPv6Header(data []byte) (*ICMPv6Header, error) {
+	if len(data) < ICMPv6HeaderSize {
+		return nil, ErrPacketTooShort
+	}
+
+	h := &ICMPv6Header{
+		Type:     data[0],
+		Code:     data[1],
+		Checksum: binary.BigEndian.Uint16(data[2:4]),
+	}
+
+	switch h.Type {
+	case ICMPv6TypeNeighborSolicitation:
+		if len(data) < 8+16 {
+			return nil, ErrPacketTooShort
+		}
+		h.TargetAddress = net.IP(data[8:24])
+		if len(data) >= 24+NDOptionSize && data[24] == 1 {
+			h.LinkLayerAddr = net.HardwareAddr(data[26:32])
+		}
+	case ICMPv6TypeNeighborAdvertisement:
+		if len(data) < 8+16 {
+			return nil, ErrPacketTooShort
+		}
+		h.Flags = binary.BigEndian.Uint32(data[4:8])
+		h.TargetAddress = net.IP(data[8:24])
+		if len(data) >= 24+NDOptionSize && data[24] == 2 {
+			h.LinkLayerAddr = net.HardwareAddr(data[26:32])
+		}
+	}
+
+	return h, nil
+}
+
+// Serialize writes the ICMPv6 header (and, for NS/NA, the target address
+// and link-layer address option) to data, then recomputes the checksum
+// over pseudoAndMessage, which must be the IPv6 pseudo-header followed by
+// the full ICMPv6 message starting at data.
+func (h *ICMPv6Header) Serialize(data []byte, pseudoAndMessage []byte) error {
+	if len(data) < ICMPv6HeaderSize {
+		return ErrBufferTooSmall
+	}
+
+	data[0] = h.Type
+	data[1] = h.Code
+	binary.BigEndian.PutUint16(data[2:4], 0)
+
+	switch h.Type {
+	case ICMPv6TypeNeighborSolicitation:
+		if len(data) < 8+16 {
+			return ErrBufferTooSmall
+		}
+		copy(data[8:24], h.TargetAddress.To16())
+		if h.LinkLayerAddr != nil && len(data) >= 24+NDOptionSize {
+			data[24], data[25] = 1, 1
+			copy(data[26:32], h.LinkLayerAddr)
+		}
+	case ICMPv6TypeNeighborAdvertisement:
+		if len(data) < 8+16 {
+			return ErrBufferTooSmall
+		}
+		binary.BigEndian.PutUint32(data[4:8], h.Flags)
+		copy(data[8:24], h.TargetAddress.To16())
+		if h.LinkLayerAddr != nil && len(data) >= 24+NDOptionSize {
+			data[24], data[25] = 2, 1
+			copy(data[26:32], h.LinkLayerAddr)
+		}
+	}
+
+	h.Checksum = cpu.Checksum(pseudoAndMessage)
+	binary.BigEndian.PutUint16(data[2:4], h.Checksum)
+	return nil
+}
+
+// ARPHeader represents an Ethernet/IPv4 ARP packet.
+type ARPHeader struct {
+	HardwareType uint16
+	ProtocolType uint16
+	Opcode       uint16
+	SenderMAC    net.HardwareAddr
+	SenderIP     net.IP
+	TargetMAC    net.HardwareAddr
+	TargetIP     net.IP
+}
+
+// ParseARPHeader parses an Ethernet/IPv4 ARP packet from a byte slice.
+func ParseARPHeader(data []byte) (*ARPHeader, error) {
+	if len(data) < ARPHeaderSize {
+		return nil, ErrPacketTooShort
+	}
+	if binary.BigEndian.Uint16(data[0:2]) != ARPHardwareTypeEthernet || binary.BigEndian.Uint16(data[2:4]) != ARPProtocolTypeIPv4 {
+		return nil, ErrUnsupportedType
+	}
+
+	return &ARPHeader{
+		HardwareType: binary.BigEndian.Uint16(data[0:2]),
+		ProtocolType: binary.BigEndian.Uint16(data[2:4]),
+		Opcode:       binary.BigEndian.Uint16(data[6:8]),
+		SenderMAC:    net.HardwareAddr(data[8:14]),
+		SenderIP:     net.IP(data[14:18]),
+		TargetMAC:    net.HardwareAddr(data[18:24]),
+		TargetIP:     net.IP(data[24:28]),
+	}, nil
+}
+
+// Serialize writes the ARP packet to a byte slice. HardwareType 0 and
+// ProtocolType 0 default to Ethernet/IPv4.
+func (h *ARPHeader) Serialize(data []byte) error {
+	if len(data) < ARPHeaderSize {
+		return ErrBufferTooSmall
+	}
+
+	hwType := h.HardwareType
+	if hwType == 0 {
+		hwType = ARPHardwareTypeEthernet
+	}
+	protoType := h.ProtocolType
+	if protoType == 0 {
+		protoType = ARPProtocolTypeIPv4
+	}
+
+	binary.BigEndian.PutUint16(data[0:2], hwType)
+	binary.BigEndian.PutUint16(data[2:4], protoType)
+	data[4] = 6 // hardware address length
+	data[5] = 4 // protocol address length
+	binary.BigEndian.PutUint16(data[6:8], h.Opcode)
+	copy(data[8:14], h.SenderMAC)
+	copy(data[14:18], h.SenderIP.To4())
+	copy(data[18:24], h.TargetMAC)
+	copy(data[24:28], h.TargetIP.To4())
+	return nil
+}
+
+// DecodedPacket holds every header Decode managed to parse, from the
+// Ethernet frame down through L4. Fields for layers the packet didn't
+// carry are left nil.
+type DecodedPacket struct {
+	Ethernet *EthernetHeader
+	VLAN     *VLANHeader
+	ARP      *ARPHeader
+	IPv4     *IPv4Header
+	IPv6     *IPv6Header
+	ICMP     *ICMPHeader
+	ICMPv6   *ICMPv6Header
+	TCP      *TCPHeader
+	UDP      *UDPHeader
+
+	// L4Payload is data sliced from after the L4 header (or after the L3
+	// header for ARP/ICMP/ICMPv6, which have no separate L4 payload
+	// field populated here); nil if there's no L3/L4 header at all.
+	L4Payload []byte
+}
+
+// Decode walks an Ethernet frame (optionally 802.1Q-tagged) down through
+// its IPv4/IPv6/ARP and TCP/UDP/ICMP/ICMPv6 headers, populating whichever
+// fields of DecodedPacket apply. It returns an error only if the
+// Ethernet header itself doesn't fit; an unparseable or unsupported
+// inner layer just stops decoding there, with everything parsed so far
+// still populated.
+func Decode(data []byte) (*DecodedPacket, error) {
+	eth, err := ParseEthernetHeader(data)
+	if err != nil {
+		return nil, err
+	}
+	out := &DecodedPacket{Ethernet: eth}
+
+	off := EthernetHeaderSize
+	etherType := eth.EtherType
+	if etherType == EtherTypeVLAN {
+		vlan, err := ParseVLANHeader(data[off:])
+		if err != nil {
+			return out, nil
+		}
+		out.VLAN = vlan
+		etherType = vlan.EtherType
+		off += VLANHeaderSize
+	}
+
+	switch etherType {
+	case EtherTypeARP:
+		arp, err := ParseARPHeader(data[off:])
+		if err == nil {
+			out.ARP = arp
+		}
+	case EtherTypeIPv4:
+		decodeIPv4(out, data[off:])
+	case EtherTypeIPv6:
+		decodeIPv6(out, data[off:])
+	}
+
+	return out, nil
+}
+
+// decodeIPv4 parses an IPv4 header and, for TCP/UDP/ICMP, the L4 header
+// that follows it, populating out.
+func decodeIPv4(out *DecodedPacket, data []byte) {
+	hdr, err := ParseIPv4Header(data)
+	if err != nil {
+		return
+	}
+	out.IPv4 = hdr
+
+	l4 := data[hdr.HeaderLength():]
+	switch hdr.Protocol {
+	case IPProtoTCP:
+		tcp, err := ParseTCPHeader(l4)
+		if err != nil {
+			return
+		}
+		out.TCP = tcp
+		out.L4Payload = l4[tcp.DataOffset:]
+	case IPProtoUDP:
+		udp, err := ParseUDPHeader(l4)
+		if err != nil {
+			return
+		}
+		out.UDP = udp
+		out.L4Payload = l4[UDPHeaderSize:]
+	case IPProtoICMP:
+		icmp, err := ParseICMPHeader(l4)
+		if err != nil {
+			return
+		}
+		out.ICMP = icmp
+		out.L4Payload = l4[ICMPHeaderSize:]
+	}
+}
+
+// decodeIPv6 parses an IPv6 header and, for TCP/UDP/ICMPv6, the L4 header
+// that follows it, populating out. It doesn't walk IPv6 extension
+// headers: NextHeader is assumed to name the L4 protocol directly.
+func decodeIPv6(out *DecodedPacket, data []byte) {
+	hdr, err := ParseIPv6Header(data)
+	if err != nil {
+		return
+	}
+	out.IPv6 = hdr
+
+	l4 := data[IPv6HeaderSize:]
+	switch hdr.NextHeader {
+	case IPProtoTCP:
+		tcp, err := ParseTCPHeader(l4)
+		if err != nil {
+			return
+		}
+		out.TCP = tcp
+		out.L4Payload = l4[tcp.DataOffset:]
+	case IPProtoUDP:
+		udp, err := ParseUDPHeader(l4)
+		if err != nil {
+			return
+		}
+		out.UDP = udp
+		out.L4Payload = l4[UDPHeaderSize:]
+	case IPProtoICMPv6:
+		icmpv6, err := ParseICMPv6Header(l4)
+		if err != nil {
+			return
+		}
+		out.ICMPv6 = icmpv6
+		out.L4Payload = l4[ICMPv6HeaderSize:]
+	}
+}
+
+// calculateUDPChecksumV4 computes the UDP checksum over an IPv4
+// pseudo-header (RFC 768) followed by the UDP datagram.
+func calculateUDPChecksumV4(srcIP, dstIP []byte, udpDatagram []byte) uint16 {
+	pseudo := make([]byte, 12+len(udpDatagram))
+	copy(pseudo[0:4], srcIP)
+	copy(pseudo[4:8], dstIP)
+	pseudo[8] = 0
+	pseudo[9] = IPProtoUDP
+	binary.BigEndian.PutUint16(pseudo[10:12], uint16(len(udpDatagram)))
+	copy(pseudo[12:], udpDatagram)
+	return cpu.Checksum(pseudo)
+}
+
+// calculateUDPChecksumV6 computes the UDP checksum over an IPv6
+// pseudo-header (RFC 2460 §8.1) followed by the UDP datagram.
+func calculateUDPChecksumV6(srcIP, dstIP []byte, udpDatagram []byte) uint16 {
+	pseudo := make([]byte, 40+len(udpDatagram))
+	copy(pseudo[0:16], srcIP)
+	copy(pseudo[16:32], dstIP)
+	binary.BigEndian.PutUint32(pseudo[32:36], uint32(len(udpDatagram)))
+	pseudo[39] = IPProtoUDP
+	copy(pseudo[40:], udpDatagram)
+	return cpu.Checksum(pseudo)
+}
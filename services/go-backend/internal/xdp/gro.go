@@ -0,0 +1,459 @@
+package xdp
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/penguintechinc/project-template/services/go-backend/internal/cpu"
+)
+
+// DefaultGSOMaxSize is the largest payload a GROTable will coalesce a flow
+// up to before forcing a flush, matching the Linux default gso_max_size.
+const DefaultGSOMaxSize = 65535
+
+// groFlowKey identifies a TCP flow by its 5-tuple. IPv6 addresses are
+// stored left-padded with zeros so the same key type covers both families.
+type groFlowKey struct {
+	srcIP    [16]byte
+	dstIP    [16]byte
+	srcPort  uint16
+	dstPort  uint16
+	protocol uint8
+}
+
+// groEntry holds the in-progress coalesced segment for one flow.
+type groEntry struct {
+	key       groFlowKey
+	pkt       []byte // full Ethernet-framed packet accumulated so far
+	ipOff     int
+	ipv6      bool
+	l4Off     int
+	seq       uint32
+	flags     uint8
+	lastTouch time.Time
+}
+
+// GROTable coalesces consecutive in-order TCP segments sharing a 5-tuple
+// into larger "super-segments" on receive, the inverse of TSOSegment. It
+// keeps a small LRU of in-progress flows; a segment that doesn't extend
+// its flow's held entry (out of order, a different TCP option length, or
+// flags other than ACK/PSH) flushes that entry and is held as the start
+// of a new one.
+type GROTable struct {
+	maxFlows   int
+	gsoMaxSize int
+
+	order   []groFlowKey // most-recently-touched last
+	entries map[groFlowKey]*groEntry
+
+	// pending holds packets evicted by insert to make room for a new flow
+	// (as opposed to evicted by Offer/Flush for a flow the caller already
+	// knows about). Offer can only return one packet per call, so these
+	// are queued here for Pending to drain rather than dropped.
+	pending [][]byte
+}
+
+// NewGROTable creates a GROTable bounded to maxFlows concurrent flows. A
+// gsoMaxSize of 0 uses DefaultGSOMaxSize.
+func NewGROTable(maxFlows, gsoMaxSize int) *GROTable {
+	if maxFlows <= 0 {
+		maxFlows = 64
+	}
+	if gsoMaxSize <= 0 {
+		gsoMaxSize = DefaultGSOMaxSize
+	}
+	return &GROTable{
+		maxFlows:   maxFlows,
+		gsoMaxSize: gsoMaxSize,
+		entries:    make(map[groFlowKey]*groEntry, maxFlows),
+	}
+}
+
+// Handler returns a PacketHandler that feeds TCP segments through the
+// table. Coalesced super-segments are only released once a later segment
+// doesn't extend the flow (flushing the prior one first) or the flow
+// reaches the table's GSO size limit, so pipelines
+// that want every resulting packet should drain Pending after every call
+// and Flush periodically (e.g. on a timer); wiring that into
+// PacketProcessor is the caller's job since PacketHandler can only
+// return zero or one packet per call.
+func (t *GROTable) Handler() PacketHandler {
+	return func(data []byte) ([]byte, bool) {
+		out, _ := t.Offer(data)
+		if out == nil {
+			// Held for further coalescing; this frame is consumed.
+			return nil, false
+		}
+		// Either data itself (not coalescable) or a super-segment this
+		// call flushed; data is then held in the table instead.
+		return out, true
+	}
+}
+
+// Offer feeds one Ethernet-framed TCP/IP segment through the table.
+// coalesced is true if data was appended to (or started) an in-progress
+// flow and so is not forwarded on its own. out is a packet ready to
+// forward, or nil: data itself, unmodified, for non-TCP packets and TCP
+// segments that can't be parsed (coalesced=false); or, with
+// coalesced=true, a flow's finalized super-segment flushed by this call,
+// either because data didn't extend it (data then starts a new entry) or
+// because it reached gsoMaxSize. Packets the table still holds come out
+// later through Pending and Flush.
+func (t *GROTable) Offer(data []byte) (out []byte, coalesced bool) {
+	key, ipOff, ipv6, ok := parseGROKey(data)
+	if !ok {
+		return data, false
+	}
+
+	l4Off, srcPort, dstPort, seq, flags, payloadOff, ok := parseGROTCP(data, ipOff, ipv6)
+	if !ok {
+		return data, false
+	}
+	key.srcPort = srcPort
+	key.dstPort = dstPort
+	payload := data[payloadOff:]
+
+	entry, exists := t.entries[key]
+	if !exists {
+		t.insert(&groEntry{
+			key:       key,
+			pkt:       append([]byte(nil), data...),
+			ipOff:     ipOff,
+			ipv6:      ipv6,
+			l4Off:     l4Off,
+			seq:       seq + uint32(len(payload)),
+			flags:     flags,
+			lastTouch: time.Now(),
+		})
+		return nil, true
+	}
+
+	if !t.canCoalesce(entry, flags, seq, l4Off, data) {
+		flushed := t.evict(key)
+		t.insert(&groEntry{
+			key:       key,
+			pkt:       append([]byte(nil), data...),
+			ipOff:     ipOff,
+			ipv6:      ipv6,
+			l4Off:     l4Off,
+			seq:       seq + uint32(len(payload)),
+			flags:     flags,
+			lastTouch: time.Now(),
+		})
+		return flushed, flushed != nil
+	}
+
+	entry.pkt = append(entry.pkt, payload...)
+	entry.seq = seq + uint32(len(payload))
+	entry.flags |= flags & (TCPFlagPSH | TCPFlagFIN)
+	entry.lastTouch = time.Now()
+	t.touch(key)
+
+	if len(entry.pkt)-payloadOffsetOf(entry) >= t.gsoMaxSize {
+		return t.evict(key), true
+	}
+	return nil, true
+}
+
+// canCoalesce reports whether a new segment extends entry in sequence,
+// carries only ACK/PSH flags, and matches the accumulated segment's TCP
+// option length (data offset).
+func (t *GROTable) canCoalesce(entry *groEntry, flags uint8, seq uint32, l4Off int, data []byte) bool {
+	if flags&^(TCPFlagACK|TCPFlagPSH) != 0 {
+		return false
+	}
+	if seq != entry.seq {
+		return false
+	}
+	if l4Off != entry.l4Off {
+		return false
+	}
+	existingDataOff := (entry.pkt[entry.l4Off+12] >> 4)
+	newDataOff := (data[l4Off+12] >> 4)
+	return existingDataOff == newDataOff
+}
+
+// payloadOffsetOf returns the byte offset where entry's TCP payload begins.
+func payloadOffsetOf(entry *groEntry) int {
+	dataOffset := int(entry.pkt[entry.l4Off+12]>>4) * 4
+	return entry.l4Off + dataOffset
+}
+
+// insert adds entry to the table, evicting the least-recently-touched
+// flow first if the table is already at capacity. An evicted flow still
+// has an accumulated, unflushed packet, so it's finalized the same way
+// evict finalizes one explicitly and queued onto pending rather than
+// discarded.
+func (t *GROTable) insert(entry *groEntry) {
+	if len(t.entries) >= t.maxFlows && len(t.order) > 0 {
+		oldest := t.order[0]
+		if pkt := t.evict(oldest); pkt != nil {
+			t.pending = append(t.pending, pkt)
+		}
+	}
+	t.entries[entry.key] = entry
+	t.order = append(t.order, entry.key)
+}
+
+// Pending drains and returns packets evicted by insert to make room for
+// a new flow. Callers using Offer/Handler should drain this after every
+// call; Offer can only return one packet per call, so an LRU eviction
+// that happens to occur while another packet is also being returned
+// would otherwise be lost.
+func (t *GROTable) Pending() [][]byte {
+	if len(t.pending) == 0 {
+		return nil
+	}
+	out := t.pending
+	t.pending = nil
+	return out
+}
+
+// touch moves key to the most-recently-used end of the LRU order.
+func (t *GROTable) touch(key groFlowKey) {
+	for i, k := range t.order {
+		if k == key {
+			t.order = append(t.order[:i], t.order[i+1:]...)
+			break
+		}
+	}
+	t.order = append(t.order, key)
+}
+
+// evict removes key from the table and returns its finalized packet with
+// updated length/checksum fields, or nil if the key wasn't present.
+func (t *GROTable) evict(key groFlowKey) []byte {
+	entry, ok := t.entries[key]
+	if !ok {
+		return nil
+	}
+	delete(t.entries, key)
+	for i, k := range t.order {
+		if k == key {
+			t.order = append(t.order[:i], t.order[i+1:]...)
+			break
+		}
+	}
+
+	finalizeGROPacket(entry)
+	return entry.pkt
+}
+
+// Flush evicts every in-progress flow and returns their finalized packets.
+// Callers should drain this periodically (e.g. on a timer) so a flow that
+// stops sending doesn't hold its last segment forever.
+func (t *GROTable) Flush() [][]byte {
+	out := make([][]byte, 0, len(t.order))
+	keys := append([]groFlowKey(nil), t.order...)
+	for _, key := range keys {
+		if pkt := t.evict(key); pkt != nil {
+			out = append(out, pkt)
+		}
+	}
+	return out
+}
+
+// parseGROKey extracts the 5-tuple groFlowKey for a TCP/IPv4 or TCP/IPv6
+// packet, returning ok=false for anything else (including VLAN-tagged
+// frames, which callers should strip before calling Offer).
+func parseGROKey(data []byte) (key groFlowKey, ipOff int, ipv6 bool, ok bool) {
+	eth, err := ParseEthernetHeader(data)
+	if err != nil {
+		return key, 0, false, false
+	}
+	ipOff = EthernetHeaderSize
+
+	switch eth.EtherType {
+	case EtherTypeIPv4:
+		hdr, err := ParseIPv4Header(data[ipOff:])
+		if err != nil || hdr.Protocol != IPProtoTCP {
+			return key, 0, false, false
+		}
+		copy(key.srcIP[12:], hdr.SrcIP.To4())
+		copy(key.dstIP[12:], hdr.DstIP.To4())
+		key.protocol = IPProtoTCP
+		return key, ipOff, false, true
+	case EtherTypeIPv6:
+		if len(data) < ipOff+IPv6HeaderSize {
+			return key, 0, false, false
+		}
+		v6 := data[ipOff:]
+		if v6[6] != IPProtoTCP {
+			return key, 0, false, false
+		}
+		copy(key.srcIP[:], v6[8:24])
+		copy(key.dstIP[:], v6[24:40])
+		key.protocol = IPProtoTCP
+		return key, ipOff, true, true
+	default:
+		return key, 0, false, false
+	}
+}
+
+// parseGROTCP parses the TCP header at ipOff+ipHeaderLen and returns its
+// ports (for the caller to fill into a groFlowKey already identified by
+// parseGROKey) along with the rest of the fields Offer/TSOSegment need.
+func parseGROTCP(data []byte, ipOff int, ipv6 bool) (l4Off int, srcPort, dstPort uint16, seq uint32, flags uint8, payloadOff int, ok bool) {
+	var ipHdrLen int
+	var totalLen int
+	if ipv6 {
+		if len(data) < ipOff+IPv6HeaderSize {
+			return 0, 0, 0, 0, 0, 0, false
+		}
+		ipHdrLen = IPv6HeaderSize
+		payloadLen := binary.BigEndian.Uint16(data[ipOff+4 : ipOff+6])
+		totalLen = int(payloadLen) + IPv6HeaderSize
+	} else {
+		hdr, err := ParseIPv4Header(data[ipOff:])
+		if err != nil {
+			return 0, 0, 0, 0, 0, 0, false
+		}
+		ipHdrLen = hdr.HeaderLength()
+		totalLen = int(hdr.TotalLen)
+	}
+
+	l4Off = ipOff + ipHdrLen
+	tcp, err := ParseTCPHeader(data[l4Off:])
+	if err != nil {
+		return 0, 0, 0, 0, 0, 0, false
+	}
+	payloadOff = l4Off + int(tcp.DataOffset)
+	if payloadOff > len(data) || ipOff+totalLen > len(data) {
+		return 0, 0, 0, 0, 0, 0, false
+	}
+	return l4Off, tcp.SrcPort, tcp.DstPort, tcp.SeqNum, tcp.Flags, payloadOff, true
+}
+
+// finalizeGROPacket rewrites the IP total length/payload length, TCP
+// flags, and both checksums of a coalesced packet so it's indistinguishable
+// from a single large segment the peer could have sent itself.
+func finalizeGROPacket(entry *groEntry) {
+	pkt := entry.pkt
+	l4Off := entry.l4Off
+
+	pkt[l4Off+13] = entry.flags | (pkt[l4Off+13] &^ (TCPFlagPSH | TCPFlagFIN))
+
+	if entry.ipv6 {
+		ipOff := entry.ipOff
+		payloadLen := len(pkt) - l4Off
+		binary.BigEndian.PutUint16(pkt[ipOff+4:ipOff+6], uint16(payloadLen))
+		tcpLen := len(pkt) - l4Off
+		checksum := calculateTCPChecksumV6(pkt[ipOff+8:ipOff+24], pkt[ipOff+24:ipOff+40], pkt[l4Off:], tcpLen)
+		binary.BigEndian.PutUint16(pkt[l4Off+16:l4Off+18], 0)
+		binary.BigEndian.PutUint16(pkt[l4Off+16:l4Off+18], checksum)
+		return
+	}
+
+	ipOff := entry.ipOff
+	ipHdr, err := ParseIPv4Header(pkt[ipOff:])
+	if err != nil {
+		return
+	}
+	ipHdr.TotalLen = uint16(len(pkt) - ipOff)
+	ipHdr.Serialize(pkt[ipOff:]) // recomputes the IPv4 checksum
+
+	tcpLen := len(pkt) - l4Off
+	binary.BigEndian.PutUint16(pkt[l4Off+16:l4Off+18], 0)
+	checksum := calculateTCPChecksumV4(ipHdr.SrcIP.To4(), ipHdr.DstIP.To4(), pkt[l4Off:], tcpLen)
+	binary.BigEndian.PutUint16(pkt[l4Off+16:l4Off+18], checksum)
+}
+
+// calculateTCPChecksumV4 computes the TCP checksum over an IPv4
+// pseudo-header (RFC 793) followed by the TCP segment.
+func calculateTCPChecksumV4(srcIP, dstIP []byte, tcpSegment []byte, tcpLen int) uint16 {
+	pseudo := make([]byte, 12+len(tcpSegment))
+	copy(pseudo[0:4], srcIP)
+	copy(pseudo[4:8], dstIP)
+	pseudo[8] = 0
+	pseudo[9] = IPProtoTCP
+	binary.BigEndian.PutUint16(pseudo[10:12], uint16(tcpLen))
+	copy(pseudo[12:], tcpSegment)
+	return cpu.Checksum(pseudo)
+}
+
+// calculateTCPChecksumV6 computes the TCP checksum over an IPv6
+// pseudo-header (RFC 2460 §8.1) followed by the TCP segment.
+func calculateTCPChecksumV6(srcIP, dstIP []byte, tcpSegment []byte, tcpLen int) uint16 {
+	pseudo := make([]byte, 40+len(tcpSegment))
+	copy(pseudo[0:16], srcIP)
+	copy(pseudo[16:32], dstIP)
+	binary.BigEndian.PutUint32(pseudo[32:36], uint32(tcpLen))
+	pseudo[39] = IPProtoTCP
+	copy(pseudo[40:], tcpSegment)
+	return cpu.Checksum(pseudo)
+}
+
+// TSOSegment chops a large TCP/IP segment (Ethernet-framed) into
+// MSS-sized frames, the inverse of GROTable's coalescing. Each output
+// frame clones the L2/L3/L4 headers of pkt, advances SeqNum by its
+// payload's offset into the original segment, sets each IPv4 frame's ID to
+// the original ID plus the frame's index, so the first frame keeps it
+// (IPv6 frames carry no per-fragment ID here since TSO output is never
+// fragmented further), and carries PSH/FIN only on the last segment.
+func TSOSegment(pkt []byte, mss uint16) [][]byte {
+	if mss == 0 {
+		return nil
+	}
+
+	_, ipOff, ipv6, ok := parseGROKey(pkt)
+	if !ok {
+		return nil
+	}
+	l4Off, _, _, seq, _, payloadOff, ok := parseGROTCP(pkt, ipOff, ipv6)
+	if !ok {
+		return nil
+	}
+
+	headers := pkt[:payloadOff]
+	payload := pkt[payloadOff:]
+	if len(payload) == 0 {
+		return [][]byte{append([]byte(nil), pkt...)}
+	}
+
+	origFlags := pkt[l4Off+13]
+	segments := make([][]byte, 0, (len(payload)+int(mss)-1)/int(mss))
+
+	for offset := 0; offset < len(payload); offset += int(mss) {
+		end := offset + int(mss)
+		if end > len(payload) {
+			end = len(payload)
+		}
+		chunkPayload := payload[offset:end]
+		isLast := end == len(payload)
+
+		seg := make([]byte, len(headers)+len(chunkPayload))
+		copy(seg, headers)
+		copy(seg[len(headers):], chunkPayload)
+
+		binary.BigEndian.PutUint32(seg[l4Off+4:l4Off+8], seq+uint32(offset))
+
+		flags := origFlags &^ (TCPFlagPSH | TCPFlagFIN)
+		if isLast {
+			flags |= origFlags & (TCPFlagPSH | TCPFlagFIN)
+		}
+		seg[l4Off+13] = flags
+
+		if ipv6 {
+			binary.BigEndian.PutUint16(seg[ipOff+4:ipOff+6], uint16(len(seg)-l4Off))
+			binary.BigEndian.PutUint16(seg[l4Off+16:l4Off+18], 0)
+			checksum := calculateTCPChecksumV6(seg[ipOff+8:ipOff+24], seg[ipOff+24:ipOff+40], seg[l4Off:], len(seg)-l4Off)
+			binary.BigEndian.PutUint16(seg[l4Off+16:l4Off+18], checksum)
+		} else {
+			ipHdr, err := ParseIPv4Header(seg[ipOff:])
+			if err != nil {
+				return nil
+			}
+			ipHdr.TotalLen = uint16(len(seg) - ipOff)
+			ipHdr.ID = ipHdr.ID + uint16(offset/int(mss))
+			ipHdr.Serialize(seg[ipOff:])
+
+			binary.BigEndian.PutUint16(seg[l4Off+16:l4Off+18], 0)
+			checksum := calculateTCPChecksumV4(ipHdr.SrcIP.To4(), ipHdr.DstIP.To4(), seg[l4Off:], len(seg)-l4Off)
+			binary.BigEndian.PutUint16(seg[l4Off+16:l4Off+18], checksum)
+		}
+
+		segments = append(segments, seg)
+	}
+
+	return segments
+}
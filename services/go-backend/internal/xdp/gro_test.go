@@ -0,0 +1,166 @@
+package xdp
+
+import (
+	"bytes"
+	"net"
+	"testing"
+)
+
+var (
+	groTestMAC1 = net.HardwareAddr{0x02, 0x00, 0x00, 0x00, 0x00, 0x01}
+	groTestMAC2 = net.HardwareAddr{0x02, 0x00, 0x00, 0x00, 0x00, 0x02}
+)
+
+// buildTCPSegment assembles an Ethernet/IPv4/TCP segment with correctly
+// computed header lengths and checksums, for use as GROTable/TSOSegment
+// test input.
+func buildTCPSegment(t *testing.T, srcPort, dstPort uint16, seq uint32, flags uint8, srcIP, dstIP net.IP, payload []byte) []byte {
+	t.Helper()
+
+	tcpOff := EthernetHeaderSize + IPv4MinHeaderSize
+	buf := make([]byte, tcpOff+TCPMinHeaderSize+len(payload))
+
+	eth := &EthernetHeader{DstMAC: groTestMAC2, SrcMAC: groTestMAC1, EtherType: EtherTypeIPv4}
+	if err := eth.Serialize(buf); err != nil {
+		t.Fatalf("EthernetHeader.Serialize: %v", err)
+	}
+
+	copy(buf[tcpOff+TCPMinHeaderSize:], payload)
+
+	tcp := &TCPHeader{
+		SrcPort:    srcPort,
+		DstPort:    dstPort,
+		SeqNum:     seq,
+		DataOffset: TCPMinHeaderSize,
+		Flags:      flags,
+		Window:     65535,
+	}
+	if err := tcp.Serialize(buf[tcpOff:], srcIP, dstIP); err != nil {
+		t.Fatalf("TCPHeader.Serialize: %v", err)
+	}
+
+	ip := &IPv4Header{
+		Version:  4,
+		IHL:      IPv4MinHeaderSize / 4,
+		TotalLen: uint16(IPv4MinHeaderSize + TCPMinHeaderSize + len(payload)),
+		TTL:      64,
+		Protocol: IPProtoTCP,
+		SrcIP:    srcIP,
+		DstIP:    dstIP,
+	}
+	if err := ip.Serialize(buf[EthernetHeaderSize:]); err != nil {
+		t.Fatalf("IPv4Header.Serialize: %v", err)
+	}
+
+	return buf
+}
+
+// TestGROTableCoalesces checks that two in-order, same-flow segments are
+// coalesced into a single held entry rather than both being forwarded.
+func TestGROTableCoalesces(t *testing.T) {
+	srcIP, dstIP := net.ParseIP("10.0.0.1"), net.ParseIP("10.0.0.2")
+	table := NewGROTable(64, 0)
+
+	seg1 := buildTCPSegment(t, 1111, 80, 1000, TCPFlagSYN|TCPFlagACK, srcIP, dstIP, []byte("hello "))
+	out, coalesced := table.Offer(seg1)
+	if out != nil || !coalesced {
+		t.Fatalf("first segment: out=%v coalesced=%v, want nil, true", out, coalesced)
+	}
+
+	seg2 := buildTCPSegment(t, 1111, 80, 1006, TCPFlagACK, srcIP, dstIP, []byte("world"))
+	out, coalesced = table.Offer(seg2)
+	if out != nil || !coalesced {
+		t.Fatalf("second segment: out=%v coalesced=%v, want nil, true", out, coalesced)
+	}
+
+	flushed := table.Flush()
+	if len(flushed) != 1 {
+		t.Fatalf("Flush returned %d packets, want 1", len(flushed))
+	}
+	tcpOff := EthernetHeaderSize + IPv4MinHeaderSize
+	got := flushed[0][tcpOff+TCPMinHeaderSize:]
+	if !bytes.Equal(got, []byte("hello world")) {
+		t.Errorf("coalesced payload = %q, want %q", got, "hello world")
+	}
+}
+
+// TestGROTableInsertEvictionQueuesPending exercises the chunk2-1 bug fix:
+// when insert evicts a flow's in-progress entry to make room for a new
+// one, the evicted packet must be recoverable from Pending rather than
+// silently dropped (Offer can only return one packet directly, for the
+// flow being flushed by canCoalesce, not for an LRU eviction of a
+// different flow entirely).
+func TestGROTableInsertEvictionQueuesPending(t *testing.T) {
+	srcIP, dstIP := net.ParseIP("10.0.0.1"), net.ParseIP("10.0.0.2")
+	table := NewGROTable(1, 0)
+
+	flowA := buildTCPSegment(t, 1111, 80, 1000, TCPFlagSYN|TCPFlagACK, srcIP, dstIP, []byte("flow-a"))
+	if out, coalesced := table.Offer(flowA); out != nil || !coalesced {
+		t.Fatalf("flow A: out=%v coalesced=%v, want nil, true", out, coalesced)
+	}
+
+	if pending := table.Pending(); pending != nil {
+		t.Fatalf("Pending before any eviction = %v, want nil", pending)
+	}
+
+	flowB := buildTCPSegment(t, 2222, 443, 2000, TCPFlagSYN|TCPFlagACK, srcIP, dstIP, []byte("flow-b"))
+	out, coalesced := table.Offer(flowB)
+	if out != nil || !coalesced {
+		t.Fatalf("flow B: out=%v coalesced=%v, want nil, true", out, coalesced)
+	}
+
+	pending := table.Pending()
+	if len(pending) != 1 {
+		t.Fatalf("Pending after flow B evicted flow A = %d packets, want 1", len(pending))
+	}
+	tcpOff := EthernetHeaderSize + IPv4MinHeaderSize
+	if got := pending[0][tcpOff+TCPMinHeaderSize:]; !bytes.Equal(got, []byte("flow-a")) {
+		t.Errorf("evicted packet payload = %q, want %q", got, "flow-a")
+	}
+	if pending := table.Pending(); pending != nil {
+		t.Errorf("second Pending() call = %v, want nil (already drained)", pending)
+	}
+}
+
+// TestTSOSegment checks that a large segment is chopped into
+// mss-sized chunks with increasing sequence numbers, PSH/FIN carried only
+// on the last chunk, and a checksum that self-verifies on every chunk.
+func TestTSOSegment(t *testing.T) {
+	srcIP, dstIP := net.ParseIP("10.0.0.1"), net.ParseIP("10.0.0.2")
+	payload := bytes.Repeat([]byte("x"), 25)
+	pkt := buildTCPSegment(t, 1111, 80, 1000, TCPFlagACK|TCPFlagPSH|TCPFlagFIN, srcIP, dstIP, payload)
+
+	segments := TSOSegment(pkt, 10)
+	if len(segments) != 3 {
+		t.Fatalf("TSOSegment produced %d segments, want 3", len(segments))
+	}
+
+	tcpOff := EthernetHeaderSize + IPv4MinHeaderSize
+	wantLens := []int{10, 10, 5}
+	for i, seg := range segments {
+		tcp, err := ParseTCPHeader(seg[tcpOff:])
+		if err != nil {
+			t.Fatalf("segment %d: ParseTCPHeader: %v", i, err)
+		}
+		wantSeq := uint32(1000 + i*10)
+		if tcp.SeqNum != wantSeq {
+			t.Errorf("segment %d: SeqNum = %d, want %d", i, tcp.SeqNum, wantSeq)
+		}
+
+		isLast := i == len(segments)-1
+		if got := tcp.Flags & (TCPFlagPSH | TCPFlagFIN); isLast && got != (TCPFlagPSH|TCPFlagFIN) {
+			t.Errorf("last segment flags = %#x, want PSH|FIN set", tcp.Flags)
+		} else if !isLast && got != 0 {
+			t.Errorf("segment %d flags = %#x, want PSH/FIN clear", i, tcp.Flags)
+		}
+
+		gotPayload := seg[tcpOff+TCPMinHeaderSize:]
+		if len(gotPayload) != wantLens[i] {
+			t.Errorf("segment %d payload length = %d, want %d", i, len(gotPayload), wantLens[i])
+		}
+
+		if got := calculateTCPChecksumV4(srcIP.To4(), dstIP.To4(), seg[tcpOff:], len(seg)-tcpOff); got != 0 {
+			t.Errorf("segment %d checksum does not self-verify: re-summing = %#04x, want 0", i, got)
+		}
+	}
+}
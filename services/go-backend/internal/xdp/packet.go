@@ -4,6 +4,13 @@ package xdp
 import (
 	"encoding/binary"
 	"net"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/penguintechinc/project-template/services/go-backend/internal/cpu"
 )
 
 // EtherType constants
@@ -141,7 +148,7 @@ func (h *IPv4Header) Serialize(data []byte) error {
 	copy(data[16:20], h.DstIP.To4())
 
 	// Calculate and set checksum
-	h.Checksum = calculateIPChecksum(data[:headerLen])
+	h.Checksum = cpu.Checksum(data[:headerLen])
 	binary.BigEndian.PutUint16(data[10:12], h.Checksum)
 
 	return nil
@@ -169,8 +176,15 @@ func ParseUDPHeader(data []byte) (*UDPHeader, error) {
 	}, nil
 }
 
-// Serialize writes the UDP header to a byte slice.
-func (h *UDPHeader) Serialize(data []byte) error {
+// Serialize writes the UDP header to a byte slice. If srcIP and dstIP are
+// both non-nil, h.Checksum is recomputed over the RFC 768/2460
+// pseudo-header followed by data[:h.Length] (data must already hold the
+// full datagram: this header plus payload). If either is nil, h.Checksum
+// is written as-is instead — a UDP checksum is optional over IPv4, and
+// callers that have already decided to send unchecked (e.g.
+// offload.Relay.Forward, which zeroes it rather than pay to recompute one
+// over a rewritten pseudo-header) shouldn't have one computed for them.
+func (h *UDPHeader) Serialize(data []byte, srcIP, dstIP net.IP) error {
 	if len(data) < UDPHeaderSize {
 		return ErrBufferTooSmall
 	}
@@ -178,6 +192,19 @@ func (h *UDPHeader) Serialize(data []byte) error {
 	binary.BigEndian.PutUint16(data[0:2], h.SrcPort)
 	binary.BigEndian.PutUint16(data[2:4], h.DstPort)
 	binary.BigEndian.PutUint16(data[4:6], h.Length)
+	binary.BigEndian.PutUint16(data[6:8], 0)
+
+	if srcIP != nil && dstIP != nil {
+		datagram := data
+		if int(h.Length) > 0 && int(h.Length) <= len(data) {
+			datagram = data[:h.Length]
+		}
+		if src4, dst4 := srcIP.To4(), dstIP.To4(); src4 != nil && dst4 != nil {
+			h.Checksum = calculateUDPChecksumV4(src4, dst4, datagram)
+		} else {
+			h.Checksum = calculateUDPChecksumV6(srcIP.To16(), dstIP.To16(), datagram)
+		}
+	}
 	binary.BigEndian.PutUint16(data[6:8], h.Checksum)
 
 	return nil
@@ -206,13 +233,20 @@ const (
 	TCPFlagURG = 0x20
 )
 
-// ParseTCPHeader parses a TCP header from a byte slice.
+// ParseTCPHeader parses a TCP header from a byte slice. DataOffset is
+// validated against data's actual length here — not just its minimum
+// possible value — since every caller uses it to slice data for the
+// options/payload that follow, and the data-offset nibble is attacker
+// controlled.
 func ParseTCPHeader(data []byte) (*TCPHeader, error) {
 	if len(data) < TCPMinHeaderSize {
 		return nil, ErrPacketTooShort
 	}
 
 	dataOffset := (data[12] >> 4) * 4
+	if dataOffset < TCPMinHeaderSize || int(dataOffset) > len(data) {
+		return nil, ErrInvalidPacket
+	}
 
 	return &TCPHeader{
 		SrcPort:    binary.BigEndian.Uint16(data[0:2]),
@@ -227,6 +261,36 @@ func ParseTCPHeader(data []byte) (*TCPHeader, error) {
 	}, nil
 }
 
+// Serialize writes the TCP header (without options) to a byte slice and
+// recomputes h.Checksum over the RFC 793/2460 pseudo-header followed by
+// data, which must be the full TCP segment: this header plus whatever
+// options and payload follow it. Unlike UDP, a TCP checksum isn't
+// optional, so srcIP/dstIP are required.
+func (h *TCPHeader) Serialize(data []byte, srcIP, dstIP net.IP) error {
+	if len(data) < TCPMinHeaderSize {
+		return ErrBufferTooSmall
+	}
+
+	binary.BigEndian.PutUint16(data[0:2], h.SrcPort)
+	binary.BigEndian.PutUint16(data[2:4], h.DstPort)
+	binary.BigEndian.PutUint32(data[4:8], h.SeqNum)
+	binary.BigEndian.PutUint32(data[8:12], h.AckNum)
+	data[12] = (h.DataOffset / 4) << 4
+	data[13] = h.Flags
+	binary.BigEndian.PutUint16(data[14:16], h.Window)
+	binary.BigEndian.PutUint16(data[16:18], 0)
+	binary.BigEndian.PutUint16(data[18:20], h.UrgentPtr)
+
+	if src4, dst4 := srcIP.To4(), dstIP.To4(); src4 != nil && dst4 != nil {
+		h.Checksum = calculateTCPChecksumV4(src4, dst4, data, len(data))
+	} else {
+		h.Checksum = calculateTCPChecksumV6(srcIP.To16(), dstIP.To16(), data, len(data))
+	}
+	binary.BigEndian.PutUint16(data[16:18], h.Checksum)
+
+	return nil
+}
+
 // Packet errors
 var (
 	ErrPacketTooShort  = packetError("packet too short")
@@ -241,58 +305,109 @@ func (e packetError) Error() string {
 	return string(e)
 }
 
-// calculateIPChecksum calculates the IPv4 header checksum.
-func calculateIPChecksum(header []byte) uint16 {
-	length := len(header)
-	var sum uint32
-
-	for i := 0; i < length-1; i += 2 {
-		sum += uint32(binary.BigEndian.Uint16(header[i : i+2]))
-	}
-
-	// Handle odd length
-	if length%2 == 1 {
-		sum += uint32(header[length-1]) << 8
-	}
-
-	// Fold 32-bit sum to 16 bits
-	for sum > 0xFFFF {
-		sum = (sum & 0xFFFF) + (sum >> 16)
-	}
-
-	return ^uint16(sum)
-}
-
-// PacketProcessor provides a pipeline for processing packets.
+// PacketProcessor provides a pipeline for processing packets. The metrics
+// fields are nil unless the processor was built with WithMetrics, in
+// which case Process records them; every hot-path check is a nil
+// comparison against a field already in cache, not an interface probe.
 type PacketProcessor struct {
 	handlers []PacketHandler
+
+	packetsProcessed prometheus.Counter
+	packetsDropped   prometheus.Counter
+	handlerDuration  *prometheus.HistogramVec
+	// handlerObservers[i] is handlerDuration.WithLabelValues for
+	// handlers[i], bound once in AddHandler so Process never computes a
+	// label set (and thus never allocates) on the hot path.
+	handlerObservers []prometheus.Observer
 }
 
 // PacketHandler is a function that processes a packet.
 // Returns true to continue processing, false to stop.
 type PacketHandler func(data []byte) ([]byte, bool)
 
-// NewPacketProcessor creates a new packet processor.
-func NewPacketProcessor() *PacketProcessor {
-	return &PacketProcessor{
+// PacketProcessorOption configures NewPacketProcessor.
+type PacketProcessorOption func(*PacketProcessor)
+
+// WithMetrics registers a packets-processed counter, a packets-dropped
+// counter, and a per-handler latency histogram on reg, and has Process
+// record them on every call. Pass an isolated registry (rather than the
+// one backing metrics.Metrics) in tests that want to observe a
+// processor's metrics without touching global state.
+func WithMetrics(reg *prometheus.Registry) PacketProcessorOption {
+	return func(p *PacketProcessor) {
+		factory := promauto.With(reg)
+
+		p.packetsProcessed = factory.NewCounter(prometheus.CounterOpts{
+			Name: "xdp_packet_processor_packets_processed_total",
+			Help: "Total number of packets that ran through every handler in the pipeline",
+		})
+		p.packetsDropped = factory.NewCounter(prometheus.CounterOpts{
+			Name: "xdp_packet_processor_packets_dropped_total",
+			Help: "Total number of packets a handler stopped before the end of the pipeline",
+		})
+		p.handlerDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "xdp_packet_processor_handler_duration_seconds",
+			Help:    "Per-handler packet processing latency",
+			Buckets: prometheus.ExponentialBuckets(1e-7, 4, 12),
+		}, []string{"handler"})
+	}
+}
+
+// NewPacketProcessor creates a new packet processor, applying opts (see
+// WithMetrics) in order.
+func NewPacketProcessor(opts ...PacketProcessorOption) *PacketProcessor {
+	p := &PacketProcessor{
 		handlers: make([]PacketHandler, 0),
 	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
 }
 
 // AddHandler adds a handler to the processing pipeline.
 func (p *PacketProcessor) AddHandler(h PacketHandler) {
 	p.handlers = append(p.handlers, h)
+	if p.handlerDuration != nil {
+		p.handlerObservers = append(p.handlerObservers, p.handlerDuration.WithLabelValues(strconv.Itoa(len(p.handlers)-1)))
+	}
 }
 
 // Process runs a packet through the processing pipeline.
 func (p *PacketProcessor) Process(data []byte) ([]byte, bool) {
 	result := data
-	for _, handler := range p.handlers {
+	for i, handler := range p.handlers {
 		var cont bool
-		result, cont = handler(result)
+		if p.handlerObservers != nil {
+			start := time.Now()
+			result, cont = handler(result)
+			p.handlerObservers[i].Observe(time.Since(start).Seconds())
+		} else {
+			result, cont = handler(result)
+		}
 		if !cont {
+			if p.packetsDropped != nil {
+				p.packetsDropped.Inc()
+			}
 			return result, false
 		}
 	}
+	if p.packetsProcessed != nil {
+		p.packetsProcessed.Inc()
+	}
 	return result, true
 }
+
+// ProcessBatch runs the first n packets in bufs (bufs[i][:sizes[i]] each)
+// through the pipeline, the same way a BatchConn.ReadBatch result is
+// meant to be consumed. It returns how many packets ran to completion
+// (i.e. weren't stopped early by a handler returning cont=false).
+func (p *PacketProcessor) ProcessBatch(bufs [][]byte, sizes []int, n int) int {
+	completed := 0
+	for i := 0; i < n; i++ {
+		if _, cont := p.Process(bufs[i][:sizes[i]]); cont {
+			completed++
+		}
+	}
+	return completed
+}
@@ -0,0 +1,101 @@
+package xdp
+
+import (
+	"net"
+	"testing"
+)
+
+// TestUDPHeaderSerializeChecksum covers the two Serialize modes added for
+// chunk2-4: a pseudo-header checksum computed when srcIP/dstIP are given,
+// and the previous write-as-is behavior preserved when either is nil (used
+// by offload.Relay.Forward, which intentionally sends an unchecked UDP
+// datagram).
+func TestUDPHeaderSerializeChecksum(t *testing.T) {
+	payload := []byte("udp payload")
+	buf := make([]byte, UDPHeaderSize+len(payload))
+	copy(buf[UDPHeaderSize:], payload)
+
+	h := &UDPHeader{SrcPort: 1234, DstPort: 53, Length: uint16(len(buf))}
+	srcIP := net.ParseIP("10.0.0.1")
+	dstIP := net.ParseIP("10.0.0.2")
+
+	if err := h.Serialize(buf, srcIP, dstIP); err != nil {
+		t.Fatalf("Serialize with IPs: %v", err)
+	}
+	if h.Checksum == 0 {
+		t.Fatal("Serialize with non-nil srcIP/dstIP left Checksum at 0")
+	}
+
+	got := calculateUDPChecksumV4(srcIP.To4(), dstIP.To4(), buf)
+	if got != 0 {
+		t.Errorf("checksum does not self-verify: re-summing with checksum in place = %#04x, want 0", got)
+	}
+
+	h2 := &UDPHeader{SrcPort: 1234, DstPort: 53, Length: uint16(len(buf)), Checksum: 0xBEEF}
+	buf2 := make([]byte, UDPHeaderSize+len(payload))
+	copy(buf2[UDPHeaderSize:], payload)
+	if err := h2.Serialize(buf2, nil, nil); err != nil {
+		t.Fatalf("Serialize with nil IPs: %v", err)
+	}
+	if h2.Checksum != 0xBEEF {
+		t.Errorf("Serialize with nil srcIP/dstIP changed Checksum to %#04x, want unchanged 0xBEEF", h2.Checksum)
+	}
+}
+
+// TestParseTCPHeaderRejectsInvalidDataOffset reproduces a panic the
+// maintainer found: a data-offset nibble claiming a header longer than
+// the buffer (e.g. the maximum value 0xF = 60 bytes, on a 20-byte
+// segment) must be rejected by ParseTCPHeader itself, since every caller
+// slices data by DataOffset and it's attacker-controlled.
+func TestParseTCPHeaderRejectsInvalidDataOffset(t *testing.T) {
+	data := make([]byte, TCPMinHeaderSize)
+	data[12] = 0xF0 // data offset nibble = 0xF -> 60 bytes, far past len(data)
+
+	if _, err := ParseTCPHeader(data); err != ErrInvalidPacket {
+		t.Fatalf("ParseTCPHeader with out-of-range data offset = %v, want ErrInvalidPacket", err)
+	}
+
+	data[12] = 0x40 // data offset nibble = 4 -> 16 bytes, below the 20-byte minimum
+	if _, err := ParseTCPHeader(data); err != ErrInvalidPacket {
+		t.Fatalf("ParseTCPHeader with below-minimum data offset = %v, want ErrInvalidPacket", err)
+	}
+}
+
+// TestTCPHeaderSerializeChecksum checks that TCPHeader.Serialize (added for
+// chunk2-4) computes a checksum that self-verifies, for both IPv4 and IPv6
+// pseudo-headers.
+func TestTCPHeaderSerializeChecksum(t *testing.T) {
+	t.Run("IPv4", func(t *testing.T) {
+		payload := []byte("tcp payload")
+		buf := make([]byte, TCPMinHeaderSize+len(payload))
+		copy(buf[TCPMinHeaderSize:], payload)
+
+		h := &TCPHeader{SrcPort: 1111, DstPort: 80, SeqNum: 1, AckNum: 2, DataOffset: TCPMinHeaderSize, Flags: TCPFlagSYN}
+		srcIP := net.ParseIP("192.168.1.1")
+		dstIP := net.ParseIP("192.168.1.2")
+
+		if err := h.Serialize(buf, srcIP, dstIP); err != nil {
+			t.Fatalf("Serialize: %v", err)
+		}
+		if got := calculateTCPChecksumV4(srcIP.To4(), dstIP.To4(), buf, len(buf)); got != 0 {
+			t.Errorf("checksum does not self-verify: re-summing with checksum in place = %#04x, want 0", got)
+		}
+	})
+
+	t.Run("IPv6", func(t *testing.T) {
+		payload := []byte("tcp payload")
+		buf := make([]byte, TCPMinHeaderSize+len(payload))
+		copy(buf[TCPMinHeaderSize:], payload)
+
+		h := &TCPHeader{SrcPort: 1111, DstPort: 80, SeqNum: 1, AckNum: 2, DataOffset: TCPMinHeaderSize, Flags: TCPFlagSYN}
+		srcIP := net.ParseIP("2001:db8::1")
+		dstIP := net.ParseIP("2001:db8::2")
+
+		if err := h.Serialize(buf, srcIP, dstIP); err != nil {
+			t.Fatalf("Serialize: %v", err)
+		}
+		if got := calculateTCPChecksumV6(srcIP.To16(), dstIP.To16(), buf, len(buf)); got != 0 {
+			t.Errorf("checksum does not self-verify: re-summing with checksum in place = %#04x, want 0", got)
+		}
+	})
+}
@@ -0,0 +1,97 @@
+package xdp
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// queueCount returns the number of RX queues ifaceName exposes, read from
+// /sys/class/net/<if>/queues/rx-* the same way `ethtool -l` ultimately
+// does, without needing the SIOCETHTOOL/ETHTOOL_GCHANNELS ioctl.
+func queueCount(ifaceName string) (int, error) {
+	entries, err := os.ReadDir(filepath.Join("/sys/class/net", ifaceName, "queues"))
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, e := range entries {
+		if e.IsDir() && strings.HasPrefix(e.Name(), "rx-") {
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, ErrInterfaceNotFound
+	}
+	return count, nil
+}
+
+// irqNamePattern matches /proc/interrupts entries for a NIC's per-queue
+// IRQs, which drivers name "<iface>-TxRx-<queue>", "<iface>-rx-<queue>"
+// or "<iface>-<queue>" depending on vendor.
+var irqNamePattern = regexp.MustCompile(`^(.+?)-(?:TxRx-|rx-)?(\d+)$`)
+
+// queueIRQCPU returns the first CPU listed in the smp_affinity_list of
+// the IRQ /proc/interrupts associates with ifaceName's queue, so
+// SocketPool can pin that queue's worker (or, in CPUMAP mode, its
+// redispatch target) to the same CPU the NIC is already interrupting.
+// It returns ok=false if no matching IRQ is found, which is common on
+// single-queue or virtual NICs; callers fall back to round-robin pinning.
+func queueIRQCPU(ifaceName string, queue int) (cpuID int, ok bool) {
+	f, err := os.Open("/proc/interrupts")
+	if err != nil {
+		return 0, false
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		name := fields[len(fields)-1]
+		m := irqNamePattern.FindStringSubmatch(name)
+		if m == nil || m[1] != ifaceName {
+			continue
+		}
+		queueIdx, err := strconv.Atoi(m[2])
+		if err != nil || queueIdx != queue {
+			continue
+		}
+
+		irq := strings.TrimSuffix(fields[0], ":")
+		return firstCPUFromAffinityList(irq)
+	}
+	return 0, false
+}
+
+// firstCPUFromAffinityList reads /proc/irq/<irq>/smp_affinity_list (a
+// comma-separated list of CPU numbers/ranges, e.g. "0,4-6") and returns
+// the first CPU in it.
+func firstCPUFromAffinityList(irq string) (int, bool) {
+	data, err := os.ReadFile(filepath.Join("/proc/irq", irq, "smp_affinity_list"))
+	if err != nil {
+		return 0, false
+	}
+
+	list := strings.TrimSpace(string(data))
+	if list == "" {
+		return 0, false
+	}
+
+	first := strings.SplitN(list, ",", 2)[0]
+	first = strings.SplitN(first, "-", 2)[0]
+
+	cpuID, err := strconv.Atoi(strings.TrimSpace(first))
+	if err != nil {
+		return 0, false
+	}
+	return cpuID, true
+}
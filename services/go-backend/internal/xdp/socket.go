@@ -5,9 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"unsafe"
 
 	"golang.org/x/sys/unix"
+
+	"github.com/penguintechinc/project-template/services/go-backend/internal/cpu"
 )
 
 var (
@@ -17,6 +20,10 @@ var (
 	ErrUMEMSetup = errors.New("failed to setup UMEM")
 	// ErrRingSetup is returned when ring buffer setup fails.
 	ErrRingSetup = errors.New("failed to setup ring buffers")
+	// ErrInvalidFrame is returned when a frame index is out of range.
+	ErrInvalidFrame = errors.New("invalid frame index")
+	// ErrNoFreeFrames is returned when Send has no free UMEM frame to use.
+	ErrNoFreeFrames = errors.New("no free TX frames available")
 )
 
 // AF_XDP socket constants
@@ -25,20 +32,83 @@ const (
 	SOL_XDP = 283
 
 	// XDP socket options
-	XDP_MMAP_OFFSETS     = 1
-	XDP_RX_RING          = 2
-	XDP_TX_RING          = 3
-	XDP_UMEM_REG         = 4
-	XDP_UMEM_FILL_RING   = 5
+	XDP_MMAP_OFFSETS         = 1
+	XDP_RX_RING              = 2
+	XDP_TX_RING              = 3
+	XDP_UMEM_REG             = 4
+	XDP_UMEM_FILL_RING       = 5
 	XDP_UMEM_COMPLETION_RING = 6
-	XDP_STATISTICS       = 7
+	XDP_STATISTICS           = 7
 
 	// XDP bind flags
-	XDP_SHARED_UMEM = 1 << 0
-	XDP_COPY        = 1 << 1
-	XDP_ZEROCOPY    = 1 << 2
+	XDP_SHARED_UMEM     = 1 << 0
+	XDP_COPY            = 1 << 1
+	XDP_ZEROCOPY        = 1 << 2
+	XDP_USE_NEED_WAKEUP = 1 << 3
+
+	// XDP_RING_NEED_WAKEUP is set by the kernel in a ring's flags word when
+	// the NAPI poller has gone to sleep and needs a sendto/poll kick.
+	XDP_RING_NEED_WAKEUP = 1 << 0
+
+	// mmap offsets for the four AF_XDP rings. The UMEM fill/completion
+	// rings live in a separate offset range from the RX/TX rings so the
+	// same fd can mmap all four at fixed pgoffsets.
+	XDP_PGOFF_RX_RING              = 0
+	XDP_PGOFF_TX_RING              = 0x80000000
+	XDP_UMEM_PGOFF_FILL_RING       = 0x100000000
+	XDP_UMEM_PGOFF_COMPLETION_RING = 0x180000000
 )
 
+// xdpRingOffset mirrors struct xdp_ring_offset from linux/if_xdp.h: the
+// byte offsets, relative to the start of a ring's mmap region, of its
+// producer index, consumer index, descriptor array and flags word.
+type xdpRingOffset struct {
+	Producer uint64
+	Consumer uint64
+	Desc     uint64
+	Flags    uint64
+}
+
+// xdpMmapOffsets mirrors struct xdp_mmap_offsets, returned by
+// getsockopt(XDP_MMAP_OFFSETS) once all four rings have been sized.
+type xdpMmapOffsets struct {
+	Rx xdpRingOffset
+	Tx xdpRingOffset
+	Fr xdpRingOffset
+	Cr xdpRingOffset
+}
+
+// xdpUmemReg mirrors struct xdp_umem_reg, passed to
+// setsockopt(XDP_UMEM_REG) to hand the kernel the UMEM region.
+type xdpUmemReg struct {
+	Addr      uint64
+	Len       uint64
+	ChunkSize uint32
+	Headroom  uint32
+	Flags     uint32
+	_         uint32 // pad to the kernel's 8-byte aligned struct size
+}
+
+// xdpDesc mirrors struct xdp_desc, the descriptor format used by the RX
+// and TX rings. The fill and completion rings instead carry bare uint64
+// frame addresses.
+type xdpDesc struct {
+	Addr    uint64
+	Len     uint32
+	Options uint32
+}
+
+// xdpStatistics mirrors struct xdp_statistics, returned by
+// getsockopt(XDP_STATISTICS).
+type xdpStatistics struct {
+	RxDropped       uint64
+	RxInvalidDescs  uint64
+	TxInvalidDescs  uint64
+	RxRingFull      uint64
+	RxFillRingEmpty uint64
+	TxRingEmpty     uint64
+}
+
 // XDPSocketConfig holds configuration for an AF_XDP socket.
 type XDPSocketConfig struct {
 	InterfaceName string
@@ -73,29 +143,58 @@ type UMEM struct {
 	numFrames int
 	frameSize int
 	headroom  int
+	freeList  chan uint64 // free frame addresses (byte offsets into data)
 }
 
 // XDPRing represents a ring buffer for AF_XDP.
 type XDPRing struct {
+	mem      []byte // mmapped region backing this ring, kept for Munmap
 	producer *uint32
 	consumer *uint32
+	flags    *uint32
 	desc     unsafe.Pointer
+	descSize int // 16 for RX/TX (xdp_desc), 8 for fill/completion (uint64)
 	mask     uint32
 	size     uint32
-	cached   uint32
+}
+
+// descAt returns a pointer to the descriptor slot at idx, wrapping at the
+// ring's size.
+func (r *XDPRing) descAt(idx uint32) unsafe.Pointer {
+	return unsafe.Pointer(uintptr(r.desc) + uintptr(idx&r.mask)*uintptr(r.descSize))
+}
+
+// frameAddr returns the fill/completion ring slot at idx as a *uint64.
+func (r *XDPRing) frameAddr(idx uint32) *uint64 {
+	return (*uint64)(r.descAt(idx))
+}
+
+// xdpDescAt returns the RX/TX ring slot at idx as an *xdpDesc.
+func (r *XDPRing) xdpDescAt(idx uint32) *xdpDesc {
+	return (*xdpDesc)(r.descAt(idx))
+}
+
+// needsWakeup reports whether the kernel has asked (via the ring's flags
+// word) to be woken with a sendto/poll. If the flags word isn't mapped we
+// wake unconditionally rather than risk a stalled TX ring.
+func (r *XDPRing) needsWakeup() bool {
+	if r.flags == nil {
+		return true
+	}
+	return atomic.LoadUint32(r.flags)&XDP_RING_NEED_WAKEUP != 0
 }
 
 // XDPSocket represents an AF_XDP socket for high-performance packet I/O.
 type XDPSocket struct {
-	fd         int
-	ifaceIdx   int
-	queueID    int
-	umem       *UMEM
-	rxRing     *XDPRing
-	txRing     *XDPRing
-	fillRing   *XDPRing
-	compRing   *XDPRing
-	config     XDPSocketConfig
+	fd       int
+	ifaceIdx int
+	queueID  int
+	umem     *UMEM
+	rxRing   *XDPRing
+	txRing   *XDPRing
+	fillRing *XDPRing
+	compRing *XDPRing
+	config   XDPSocketConfig
 
 	rxChan chan []byte
 	txChan chan []byte
@@ -107,6 +206,18 @@ type XDPSocket struct {
 // NewXDPSocket creates a new AF_XDP socket.
 // Note: This requires CAP_NET_ADMIN and CAP_SYS_ADMIN capabilities.
 func NewXDPSocket(config XDPSocketConfig) (*XDPSocket, error) {
+	if !isPowerOfTwo(config.FrameSize) {
+		return nil, fmt.Errorf("%w: frame size %d must be a power of two", ErrUMEMSetup, config.FrameSize)
+	}
+	for name, size := range map[string]int{
+		"rx ring": config.RxRingSize, "tx ring": config.TxRingSize,
+		"fill ring": config.FillRingSize, "completion ring": config.CompRingSize,
+	} {
+		if !isPowerOfTwo(size) {
+			return nil, fmt.Errorf("%w: %s size %d must be a power of two", ErrRingSetup, name, size)
+		}
+	}
+
 	ifaceIdx, err := GetInterfaceIndex(config.InterfaceName)
 	if err != nil {
 		return nil, err
@@ -148,6 +259,12 @@ func NewXDPSocket(config XDPSocketConfig) (*XDPSocket, error) {
 	return sock, nil
 }
 
+// isPowerOfTwo reports whether n is a positive power of two, as required
+// for AF_XDP frame sizes and ring sizes so index masking works.
+func isPowerOfTwo(n int) bool {
+	return n > 0 && n&(n-1) == 0
+}
+
 // setupUMEM allocates and registers the UMEM region.
 func (s *XDPSocket) setupUMEM() error {
 	totalSize := s.config.NumFrames * s.config.FrameSize
@@ -165,49 +282,132 @@ func (s *XDPSocket) setupUMEM() error {
 		// Non-fatal, continue anyway
 	}
 
+	freeList := make(chan uint64, s.config.NumFrames)
+	for i := 0; i < s.config.NumFrames; i++ {
+		freeList <- uint64(i) * uint64(s.config.FrameSize)
+	}
+
 	s.umem = &UMEM{
 		data:      data,
 		numFrames: s.config.NumFrames,
 		frameSize: s.config.FrameSize,
+		freeList:  freeList,
 	}
 
-	// Register UMEM with kernel
-	// This would use the XDP_UMEM_REG socket option
-	// Omitted for brevity - requires struct definitions matching kernel
+	// Register UMEM with the kernel so it knows which process memory
+	// backs the rings we're about to set up.
+	reg := xdpUmemReg{
+		Addr:      uint64(uintptr(unsafe.Pointer(&data[0]))),
+		Len:       uint64(totalSize),
+		ChunkSize: uint32(s.config.FrameSize),
+		Headroom:  uint32(s.umem.headroom),
+	}
+	if err := setsockopt(s.fd, SOL_XDP, XDP_UMEM_REG, unsafe.Pointer(&reg), unsafe.Sizeof(reg)); err != nil {
+		unix.Munmap(data)
+		return fmt.Errorf("%w: setsockopt(XDP_UMEM_REG): %v", ErrUMEMSetup, err)
+	}
 
 	return nil
 }
 
-// setupRings configures the ring buffers.
+// setupRings sizes the four AF_XDP rings, learns their mmap layout via
+// getsockopt(XDP_MMAP_OFFSETS), and mmaps each at its fixed pgoffset.
 func (s *XDPSocket) setupRings() error {
-	// Ring buffer setup requires mmap of specific offsets
-	// This is a simplified skeleton - full implementation requires
-	// kernel struct definitions and mmap calls
+	if err := setsockoptUint32(s.fd, SOL_XDP, XDP_UMEM_FILL_RING, uint32(s.config.FillRingSize)); err != nil {
+		return fmt.Errorf("%w: setsockopt(XDP_UMEM_FILL_RING): %v", ErrRingSetup, err)
+	}
+	if err := setsockoptUint32(s.fd, SOL_XDP, XDP_UMEM_COMPLETION_RING, uint32(s.config.CompRingSize)); err != nil {
+		return fmt.Errorf("%w: setsockopt(XDP_UMEM_COMPLETION_RING): %v", ErrRingSetup, err)
+	}
+	if err := setsockoptUint32(s.fd, SOL_XDP, XDP_RX_RING, uint32(s.config.RxRingSize)); err != nil {
+		return fmt.Errorf("%w: setsockopt(XDP_RX_RING): %v", ErrRingSetup, err)
+	}
+	if err := setsockoptUint32(s.fd, SOL_XDP, XDP_TX_RING, uint32(s.config.TxRingSize)); err != nil {
+		return fmt.Errorf("%w: setsockopt(XDP_TX_RING): %v", ErrRingSetup, err)
+	}
+
+	var offsets xdpMmapOffsets
+	size := uint32(unsafe.Sizeof(offsets))
+	if err := getsockopt(s.fd, SOL_XDP, XDP_MMAP_OFFSETS, unsafe.Pointer(&offsets), &size); err != nil {
+		return fmt.Errorf("%w: getsockopt(XDP_MMAP_OFFSETS): %v", ErrRingSetup, err)
+	}
 
-	s.rxRing = &XDPRing{
-		size: uint32(s.config.RxRingSize),
-		mask: uint32(s.config.RxRingSize - 1),
+	xdpDescSize := int(unsafe.Sizeof(xdpDesc{}))
+
+	rx, err := mmapRing(s.fd, XDP_PGOFF_RX_RING, offsets.Rx, uint32(s.config.RxRingSize), xdpDescSize)
+	if err != nil {
+		return fmt.Errorf("%w: rx ring: %v", ErrRingSetup, err)
 	}
-	s.txRing = &XDPRing{
-		size: uint32(s.config.TxRingSize),
-		mask: uint32(s.config.TxRingSize - 1),
+	tx, err := mmapRing(s.fd, XDP_PGOFF_TX_RING, offsets.Tx, uint32(s.config.TxRingSize), xdpDescSize)
+	if err != nil {
+		return fmt.Errorf("%w: tx ring: %v", ErrRingSetup, err)
 	}
-	s.fillRing = &XDPRing{
-		size: uint32(s.config.FillRingSize),
-		mask: uint32(s.config.FillRingSize - 1),
+	fill, err := mmapRing(s.fd, XDP_UMEM_PGOFF_FILL_RING, offsets.Fr, uint32(s.config.FillRingSize), 8)
+	if err != nil {
+		return fmt.Errorf("%w: fill ring: %v", ErrRingSetup, err)
 	}
-	s.compRing = &XDPRing{
-		size: uint32(s.config.CompRingSize),
-		mask: uint32(s.config.CompRingSize - 1),
+	comp, err := mmapRing(s.fd, XDP_UMEM_PGOFF_COMPLETION_RING, offsets.Cr, uint32(s.config.CompRingSize), 8)
+	if err != nil {
+		return fmt.Errorf("%w: completion ring: %v", ErrRingSetup, err)
+	}
+
+	s.rxRing = rx
+	s.txRing = tx
+	s.fillRing = fill
+	s.compRing = comp
+
+	// Seed the fill ring with half the UMEM frames so the kernel has RX
+	// buffers to write into as soon as the socket is bound; the other
+	// half stays in the free list for Send to hand out as TX buffers.
+	initialFill := s.config.FillRingSize
+	if half := s.umem.numFrames / 2; initialFill > half {
+		initialFill = half
+	}
+	for i := 0; i < initialFill; i++ {
+		addr, ok := <-s.umem.freeList
+		if !ok {
+			break
+		}
+		idx := atomic.LoadUint32(s.fillRing.producer)
+		*s.fillRing.frameAddr(idx) = addr
+		atomic.StoreUint32(s.fillRing.producer, idx+1)
 	}
 
 	return nil
 }
 
+// mmapRing mmaps a single AF_XDP ring at pgoff and locates its
+// producer/consumer/flags words and descriptor array using the offsets
+// the kernel reported for it.
+func mmapRing(fd int, pgoff int64, off xdpRingOffset, nentries uint32, descSize int) (*XDPRing, error) {
+	ringBytes := int(off.Desc) + int(nentries)*descSize
+	mem, err := unix.Mmap(fd, pgoff, ringBytes, unix.PROT_READ|unix.PROT_WRITE, unix.MAP_SHARED|unix.MAP_POPULATE)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each offset must be added to &mem[0] within the same unsafe.Pointer
+	// conversion expression: staging the base address through an
+	// intermediate uintptr variable (as this used to do) gives the
+	// garbage collector a window to move mem and invalidate the
+	// arithmetic, which is exactly what go vet's "possible misuse of
+	// unsafe.Pointer" flags.
+	return &XDPRing{
+		mem:      mem,
+		producer: (*uint32)(unsafe.Pointer(uintptr(unsafe.Pointer(&mem[0])) + uintptr(off.Producer))),
+		consumer: (*uint32)(unsafe.Pointer(uintptr(unsafe.Pointer(&mem[0])) + uintptr(off.Consumer))),
+		flags:    (*uint32)(unsafe.Pointer(uintptr(unsafe.Pointer(&mem[0])) + uintptr(off.Flags))),
+		desc:     unsafe.Pointer(uintptr(unsafe.Pointer(&mem[0])) + uintptr(off.Desc)),
+		descSize: descSize,
+		mask:     nentries - 1,
+		size:     nentries,
+	}, nil
+}
+
 // bind binds the socket to an interface and queue.
 func (s *XDPSocket) bind() error {
 	sa := &unix.SockaddrXDP{
-		Flags:   0,
+		Flags:   XDP_USE_NEED_WAKEUP,
 		Ifindex: uint32(s.ifaceIdx),
 		QueueID: uint32(s.queueID),
 	}
@@ -222,7 +422,9 @@ func (s *XDPSocket) bind() error {
 }
 
 // Receive receives a packet from the socket.
-// Returns the packet data and the frame index (for returning to fill ring).
+// Returns the packet data (a zero-copy view into the UMEM, valid until
+// ReturnFrame is called) and the frame index to pass to ReturnFrame once
+// the caller is done with it.
 func (s *XDPSocket) Receive() ([]byte, int, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -231,27 +433,33 @@ func (s *XDPSocket) Receive() ([]byte, int, error) {
 		return nil, 0, errors.New("socket closed")
 	}
 
-	// Poll for data
-	pollFds := []unix.PollFd{{
-		Fd:     int32(s.fd),
-		Events: unix.POLLIN,
-	}}
-
-	n, err := unix.Poll(pollFds, 1000) // 1 second timeout
-	if err != nil {
-		return nil, 0, err
+	consumer := atomic.LoadUint32(s.rxRing.consumer)
+	if atomic.LoadUint32(s.rxRing.producer) == consumer {
+		pollFds := []unix.PollFd{{
+			Fd:     int32(s.fd),
+			Events: unix.POLLIN,
+		}}
+
+		n, err := unix.Poll(pollFds, 1000) // 1 second timeout
+		if err != nil {
+			return nil, 0, err
+		}
+		if n == 0 {
+			return nil, 0, nil // Timeout, no data
+		}
+		if atomic.LoadUint32(s.rxRing.producer) == consumer {
+			return nil, 0, nil
+		}
 	}
 
-	if n == 0 {
-		return nil, 0, nil // Timeout, no data
-	}
+	desc := s.rxRing.xdpDescAt(consumer)
+	addr, length := desc.Addr, desc.Len
 
-	// In a real implementation, we would:
-	// 1. Read from the RX ring
-	// 2. Get the frame descriptor
-	// 3. Return the data slice and frame index
+	atomic.StoreUint32(s.rxRing.consumer, consumer+1)
 
-	return nil, 0, nil
+	frameIdx := int(addr) / s.umem.frameSize
+	start := int(addr)
+	return s.umem.data[start : start+int(length)], frameIdx, nil
 }
 
 // Send sends a packet through the socket.
@@ -267,21 +475,174 @@ func (s *XDPSocket) Send(data []byte) error {
 		return errors.New("packet too large")
 	}
 
-	// In a real implementation, we would:
-	// 1. Get a frame from the completion ring
-	// 2. Copy data to the frame
-	// 3. Add to TX ring
-	// 4. Kick the kernel to send
+	s.reclaimCompletions()
+
+	var addr uint64
+	select {
+	case addr = <-s.umem.freeList:
+	default:
+		return ErrNoFreeFrames
+	}
+
+	start := int(addr)
+	cpu.CopyFrame(s.umem.data[start:start+len(data)], data)
+
+	producer := atomic.LoadUint32(s.txRing.producer)
+	desc := s.txRing.xdpDescAt(producer)
+	desc.Addr = addr
+	desc.Len = uint32(len(data))
+	desc.Options = 0
+	atomic.StoreUint32(s.txRing.producer, producer+1)
+
+	if s.txRing.needsWakeup() {
+		if err := unix.Sendto(s.fd, nil, unix.MSG_DONTWAIT, nil); err != nil && err != unix.EAGAIN {
+			return fmt.Errorf("sendto wakeup: %w", err)
+		}
+	}
 
 	return nil
 }
 
+// reclaimCompletions drains the completion ring, returning transmitted
+// frames to the UMEM free list so Send can reuse them.
+func (s *XDPSocket) reclaimCompletions() {
+	producer := atomic.LoadUint32(s.compRing.producer)
+	consumer := atomic.LoadUint32(s.compRing.consumer)
+
+	for consumer != producer {
+		addr := *s.compRing.frameAddr(consumer)
+		consumer++
+		select {
+		case s.umem.freeList <- addr:
+		default:
+			// Free list full (shouldn't happen, every frame is accounted
+			// for exactly once); drop rather than block.
+		}
+	}
+
+	atomic.StoreUint32(s.compRing.consumer, consumer)
+}
+
 // ReturnFrame returns a frame to the fill ring after processing.
 func (s *XDPSocket) ReturnFrame(frameIdx int) error {
-	// Add frame index to fill ring so kernel can use it for new packets
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return errors.New("socket closed")
+	}
+	if frameIdx < 0 || frameIdx >= s.umem.numFrames {
+		return ErrInvalidFrame
+	}
+
+	s.returnFrameLocked(frameIdx)
 	return nil
 }
 
+// Poll drains up to batch received frames, blocking (with the same 1
+// second timeout as Receive) only when none are yet available. Unlike
+// Receive, each returned slice is an owned copy rather than a zero-copy
+// view into the UMEM: Poll returns every frame to the fill ring itself
+// before returning, so batch consumers don't need to track frame indices.
+func (s *XDPSocket) Poll(batch int) ([][]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil, errors.New("socket closed")
+	}
+	if batch <= 0 {
+		return nil, nil
+	}
+
+	frames := make([][]byte, 0, batch)
+	for len(frames) < batch {
+		consumer := atomic.LoadUint32(s.rxRing.consumer)
+		if atomic.LoadUint32(s.rxRing.producer) == consumer {
+			if len(frames) > 0 {
+				break
+			}
+
+			pollFds := []unix.PollFd{{
+				Fd:     int32(s.fd),
+				Events: unix.POLLIN,
+			}}
+			n, err := unix.Poll(pollFds, 1000)
+			if err != nil {
+				return nil, err
+			}
+			if n == 0 || atomic.LoadUint32(s.rxRing.producer) == consumer {
+				return frames, nil
+			}
+			continue
+		}
+
+		desc := s.rxRing.xdpDescAt(consumer)
+		addr, length := desc.Addr, desc.Len
+		atomic.StoreUint32(s.rxRing.consumer, consumer+1)
+
+		start := int(addr)
+		frames = append(frames, append([]byte(nil), s.umem.data[start:start+int(length)]...))
+
+		s.returnFrameLocked(int(addr) / s.umem.frameSize)
+	}
+
+	return frames, nil
+}
+
+// Transmit sends each frame in order through Send, stopping at the first
+// error (typically ErrNoFreeFrames if the TX side of the UMEM is
+// saturated).
+func (s *XDPSocket) Transmit(frames [][]byte) error {
+	for _, frame := range frames {
+		if err := s.Send(frame); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RingDepths reports the current occupancy of all four AF_XDP rings, i.e.
+// how many descriptors the producer is ahead of the consumer. It's a
+// point-in-time snapshot useful for exposing backpressure (e.g. a full RX
+// ring means userspace isn't draining fast enough) through a status
+// endpoint.
+type XDPRingDepths struct {
+	RxRingDepth   uint32
+	TxRingDepth   uint32
+	FillRingDepth uint32
+	CompRingDepth uint32
+}
+
+// RingDepths returns the current depth of each ring.
+func (s *XDPSocket) RingDepths() XDPRingDepths {
+	return XDPRingDepths{
+		RxRingDepth:   ringDepth(s.rxRing),
+		TxRingDepth:   ringDepth(s.txRing),
+		FillRingDepth: ringDepth(s.fillRing),
+		CompRingDepth: ringDepth(s.compRing),
+	}
+}
+
+// ringDepth returns how many descriptors are currently queued between a
+// ring's consumer and producer indices.
+func ringDepth(r *XDPRing) uint32 {
+	return atomic.LoadUint32(r.producer) - atomic.LoadUint32(r.consumer)
+}
+
+// returnFrameLocked is ReturnFrame's body, split out so Poll can return a
+// frame to the fill ring while already holding s.mu.
+func (s *XDPSocket) returnFrameLocked(frameIdx int) {
+	if frameIdx < 0 || frameIdx >= s.umem.numFrames {
+		return
+	}
+	addr := uint64(frameIdx) * uint64(s.umem.frameSize)
+
+	idx := atomic.LoadUint32(s.fillRing.producer)
+	*s.fillRing.frameAddr(idx) = addr
+	atomic.StoreUint32(s.fillRing.producer, idx+1)
+}
+
 // Stats returns socket statistics.
 type XDPSocketStats struct {
 	RxDropped    uint64
@@ -294,8 +655,20 @@ type XDPSocketStats struct {
 
 // Stats retrieves socket statistics.
 func (s *XDPSocket) Stats() (*XDPSocketStats, error) {
-	// Use getsockopt with XDP_STATISTICS
-	return &XDPSocketStats{}, nil
+	var stats xdpStatistics
+	size := uint32(unsafe.Sizeof(stats))
+	if err := getsockopt(s.fd, SOL_XDP, XDP_STATISTICS, unsafe.Pointer(&stats), &size); err != nil {
+		return nil, fmt.Errorf("getsockopt(XDP_STATISTICS): %w", err)
+	}
+
+	return &XDPSocketStats{
+		RxDropped:    stats.RxDropped,
+		RxInvalid:    stats.RxInvalidDescs,
+		TxInvalid:    stats.TxInvalidDescs,
+		RxRingFull:   stats.RxRingFull,
+		FillRingFull: stats.RxFillRingEmpty,
+		TxRingFull:   stats.TxRingEmpty,
+	}, nil
 }
 
 // FileDescriptor returns the socket file descriptor.
@@ -316,10 +689,45 @@ func (s *XDPSocket) Close() error {
 	close(s.rxChan)
 	close(s.txChan)
 
-	// Unmap UMEM
-	if s.umem != nil && s.umem.data != nil {
-		unix.Munmap(s.umem.data)
+	for _, ring := range []*XDPRing{s.rxRing, s.txRing, s.fillRing, s.compRing} {
+		if ring != nil && ring.mem != nil {
+			unix.Munmap(ring.mem)
+		}
+	}
+
+	if s.umem != nil {
+		if s.umem.data != nil {
+			unix.Munmap(s.umem.data)
+		}
+		close(s.umem.freeList)
 	}
 
 	return unix.Close(s.fd)
 }
+
+// setsockopt issues a raw setsockopt(2) call; golang.org/x/sys/unix has no
+// typed wrapper for the AF_XDP options, so we go through the syscall
+// directly, the same way the NUMA current-node lookup elsewhere in this
+// service reaches for SYS_GETCPU.
+func setsockopt(fd, level, name int, val unsafe.Pointer, size uintptr) error {
+	_, _, errno := unix.Syscall6(unix.SYS_SETSOCKOPT, uintptr(fd), uintptr(level), uintptr(name), uintptr(val), size, 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// setsockoptUint32 is a convenience wrapper for the XDP ring-sizing
+// options, which all take a single uint32.
+func setsockoptUint32(fd, level, name int, val uint32) error {
+	return setsockopt(fd, level, name, unsafe.Pointer(&val), unsafe.Sizeof(val))
+}
+
+// getsockopt issues a raw getsockopt(2) call; see setsockopt.
+func getsockopt(fd, level, name int, val unsafe.Pointer, size *uint32) error {
+	_, _, errno := unix.Syscall6(unix.SYS_GETSOCKOPT, uintptr(fd), uintptr(level), uintptr(name), uintptr(val), uintptr(unsafe.Pointer(size)), 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
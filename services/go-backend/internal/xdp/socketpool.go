@@ -0,0 +1,236 @@
+package xdp
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/penguintechinc/project-template/services/go-backend/internal/memory"
+)
+
+// SocketPool opens one XDPSocket per RX queue of an interface, registers
+// each socket's fd in the dispatcher's xsks_map and marks its queue
+// redirected (under FanoutCPUMap the dispatcher steers the queue through
+// cpu_map rather than xsks_map), and fans received packets out into
+// per-queue channels of pool-backed buffers. Queues whose IRQ affinity is
+// discoverable are pinned to the CPU their IRQ already runs on; the rest
+// are assigned round-robin.
+type SocketPool struct {
+	program *XDPProgram
+	bufPool *memory.BufferPool
+
+	queues []*poolQueue
+	wg     sync.WaitGroup
+	closed atomic.Bool
+}
+
+// poolQueue is one RX queue's socket, worker, and output channel.
+type poolQueue struct {
+	queueID int
+	cpuID   int
+	sock    *XDPSocket
+	out     chan *memory.Buffer
+	stop    chan struct{}
+}
+
+// NewSocketPool queries ifaceName's RX queue count, opens an XDPSocket per
+// queue built from socketConfig (InterfaceName and QueueID are overwritten
+// per queue), and registers each with program according to fanout.
+// bufPool supplies the buffers handed out on each
+// queue's channel.
+func NewSocketPool(program *XDPProgram, socketConfig XDPSocketConfig, fanout FanoutPolicy, numaNodeID int, bufPool *memory.BufferPool) (*SocketPool, error) {
+	ifaceName := program.InterfaceName()
+
+	numQueues, err := queueCount(ifaceName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine queue count for %s: %w", ifaceName, err)
+	}
+
+	if err := setFanoutMode(program, fanout); err != nil {
+		return nil, err
+	}
+
+	pool := &SocketPool{
+		program: program,
+		bufPool: bufPool,
+		queues:  make([]*poolQueue, 0, numQueues),
+	}
+
+	info := memory.GetNUMAInfo()
+	cpusForNode := info.CPUsPerNode[numaNodeID]
+
+	for q := 0; q < numQueues; q++ {
+		cfg := socketConfig
+		cfg.InterfaceName = ifaceName
+		cfg.QueueID = q
+
+		sock, err := NewXDPSocket(cfg)
+		if err != nil {
+			pool.Close()
+			return nil, fmt.Errorf("queue %d: %w", q, err)
+		}
+
+		cpuID, ok := queueIRQCPU(ifaceName, q)
+		if !ok {
+			cpuID = roundRobinCPU(cpusForNode, q)
+		}
+
+		if err := registerQueue(program, fanout, q, cpuID); err != nil {
+			sock.Close()
+			pool.Close()
+			return nil, fmt.Errorf("queue %d: %w", q, err)
+		}
+
+		pq := &poolQueue{
+			queueID: q,
+			cpuID:   cpuID,
+			sock:    sock,
+			out:     make(chan *memory.Buffer, socketConfig.RxRingSize),
+			stop:    make(chan struct{}),
+		}
+		pool.queues = append(pool.queues, pq)
+
+		pool.wg.Add(1)
+		go pool.runQueue(pq)
+	}
+
+	return pool, nil
+}
+
+// roundRobinCPU picks a CPU for queue q from cpus (the NUMA node's CPU
+// list) when the queue's IRQ affinity can't be determined, cycling
+// through the list so queues spread evenly across the node's cores.
+func roundRobinCPU(cpus []int, q int) int {
+	if len(cpus) == 0 {
+		return 0
+	}
+	return cpus[q%len(cpus)]
+}
+
+// setFanoutMode writes the dispatcher's single fanout_mode entry.
+func setFanoutMode(program *XDPProgram, fanout FanoutPolicy) error {
+	var mode uint32
+	if fanout == FanoutCPUMap {
+		mode = 1
+	}
+	key := uint32(0)
+	if err := program.FanoutMode().Put(&key, &mode); err != nil {
+		return fmt.Errorf("failed to set fanout_mode: %w", err)
+	}
+	return nil
+}
+
+// registerQueue marks queue as redirected and, for CPUMap fan-out, maps it
+// to cpuID in queue_to_cpu and gives cpuID a cpu_map entry. The socket's
+// fd is registered in xsks_map separately, by runQueue, in both modes.
+func registerQueue(program *XDPProgram, fanout FanoutPolicy, queue, cpuID int) error {
+	queueKey := uint32(queue)
+	redirect := uint8(1)
+	if err := program.RedirectFlows().Put(&queueKey, &redirect); err != nil {
+		return fmt.Errorf("failed to mark queue %d redirected: %w", queue, err)
+	}
+
+	if fanout == FanoutCPUMap {
+		cpu := uint32(cpuID)
+		if err := program.QueueToCPU().Put(&queueKey, &cpu); err != nil {
+			return fmt.Errorf("failed to map queue %d to cpu %d: %w", queue, cpuID, err)
+		}
+		// A cpu_map value is the queue size of that CPU's redispatch
+		// kthread; with no entry, the dispatcher's redirect falls back to
+		// XDP_PASS.
+		qsize := uint32(2048)
+		if err := program.CPUMap().Put(&cpu, &qsize); err != nil {
+			return fmt.Errorf("failed to add cpu %d to cpu_map: %w", cpuID, err)
+		}
+		return nil
+	}
+
+	return nil
+}
+
+// runQueue pins the calling goroutine to pq's CPU, registers its socket's
+// fd in xsks_map, and copies received packets into pool-backed buffers
+// until Close is called.
+func (p *SocketPool) runQueue(pq *poolQueue) {
+	defer p.wg.Done()
+	defer close(pq.out)
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	var cpuSet unix.CPUSet
+	cpuSet.Set(pq.cpuID)
+	if err := unix.SchedSetaffinity(0, &cpuSet); err != nil {
+		// Non-fatal: pinning is a best-effort placement hint.
+		_ = err
+	}
+
+	queueKey := uint32(pq.queueID)
+	fd := uint32(pq.sock.FileDescriptor())
+	if err := p.program.XSKMap().Put(&queueKey, &fd); err != nil {
+		// Registration failure leaves the queue passed up the normal
+		// network stack instead of redirected; nothing more to do here.
+		return
+	}
+
+	for {
+		select {
+		case <-pq.stop:
+			return
+		default:
+		}
+
+		data, frameIdx, err := pq.sock.Receive()
+		if err != nil || data == nil {
+			continue
+		}
+
+		buf, err := p.bufPool.Get()
+		if err != nil {
+			pq.sock.ReturnFrame(frameIdx)
+			continue
+		}
+		buf.Write(data)
+		pq.sock.ReturnFrame(frameIdx)
+
+		select {
+		case pq.out <- buf:
+		case <-pq.stop:
+			p.bufPool.Put(buf)
+			return
+		}
+	}
+}
+
+// Queues returns the receive channels for every RX queue the pool opened,
+// indexed by queue ID.
+func (p *SocketPool) Queues() []<-chan *memory.Buffer {
+	chans := make([]<-chan *memory.Buffer, len(p.queues))
+	for i, pq := range p.queues {
+		chans[i] = pq.out
+	}
+	return chans
+}
+
+// Close stops every queue's receive worker and closes its socket.
+func (p *SocketPool) Close() error {
+	if !p.closed.CompareAndSwap(false, true) {
+		return nil
+	}
+
+	for _, pq := range p.queues {
+		close(pq.stop)
+	}
+	p.wg.Wait()
+
+	var firstErr error
+	for _, pq := range p.queues {
+		if err := pq.sock.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -10,6 +10,8 @@ import (
 	"github.com/cilium/ebpf"
 	"github.com/cilium/ebpf/link"
 	"golang.org/x/sys/unix"
+
+	"github.com/penguintechinc/project-template/services/go-backend/internal/xdp/bpf"
 )
 
 // XDPMode represents the XDP attach mode.
@@ -31,6 +33,10 @@ var (
 	ErrXDPNotSupported = errors.New("XDP not supported on this system")
 	// ErrInterfaceNotFound is returned when the network interface doesn't exist.
 	ErrInterfaceNotFound = errors.New("network interface not found")
+	// ErrDispatcherNotBuilt is returned when dispatcher_bpfel.o is still
+	// the placeholder checked into internal/xdp/bpf rather than a real
+	// compiled object (see bpf.IsDispatcherBuilt).
+	ErrDispatcherNotBuilt = errors.New("xdp: dispatcher_bpfel.o is a placeholder; run ./fetch-headers.sh && go generate ./internal/xdp/bpf with clang/libbpf installed, then rebuild")
 )
 
 // XDPProgram represents a loaded XDP program.
@@ -39,7 +45,7 @@ type XDPProgram struct {
 	ifaceIdx  int
 	mode      XDPMode
 	link      link.Link
-	prog      *ebpf.Program
+	objs      bpf.DispatcherObjects
 }
 
 // XDPConfig holds configuration for XDP program loading.
@@ -47,8 +53,25 @@ type XDPConfig struct {
 	InterfaceName string
 	Mode          XDPMode
 	ProgramPath   string // Path to compiled eBPF object file
+	FanoutPolicy  FanoutPolicy
+	NUMANodeID    int
 }
 
+// FanoutPolicy selects how SocketPool fans incoming traffic out across
+// CPUs.
+type FanoutPolicy int
+
+const (
+	// FanoutPerQueue opens one AF_XDP socket per RX queue, each pinned to
+	// the CPU its queue's IRQ is affine to. Best when the NIC has at
+	// least as many combined queues as the cores that should service it.
+	FanoutPerQueue FanoutPolicy = iota
+	// FanoutCPUMap redirects every registered queue through a single
+	// BPF_MAP_TYPE_CPUMAP, letting a kernel kthread per CPU do the
+	// redispatch. Best when the NIC has fewer queues than cores.
+	FanoutCPUMap
+)
+
 // ParseXDPMode parses a string mode to XDPMode.
 func ParseXDPMode(mode string) XDPMode {
 	switch mode {
@@ -70,9 +93,13 @@ func IsXDPSupported() bool {
 		return false
 	}
 
-	// Check for CAP_BPF or CAP_SYS_ADMIN
-	// In practice, we need to be root or have specific capabilities
-	return os.Geteuid() == 0
+	// Probe the bpf() syscall itself rather than just checking uid: a
+	// kernel without CONFIG_BPF_SYSCALL returns ENOSYS, while one that
+	// has it but denies us the capability still returns EPERM or
+	// EINVAL, which we want to treat as "supported" since the caller
+	// may still be able to attach via a more privileged process.
+	_, _, errno := unix.Syscall(unix.SYS_BPF, uintptr(unix.BPF_PROG_LOAD), 0, 0)
+	return errno != unix.ENOSYS
 }
 
 // GetInterfaceIndex returns the index of a network interface.
@@ -84,47 +111,133 @@ func GetInterfaceIndex(name string) (int, error) {
 	return iface.Index, nil
 }
 
-// LoadXDPProgram loads an XDP program from an eBPF object file.
-// Note: This is a skeleton implementation. In production, you would
-// compile actual eBPF C code and load it here.
+// LoadXDPProgram loads the embedded dispatcher eBPF program (see
+// internal/xdp/bpf) and attaches it to an interface.
 func LoadXDPProgram(config XDPConfig) (*XDPProgram, error) {
 	if !IsXDPSupported() {
 		return nil, ErrXDPNotSupported
 	}
 
+	if !bpf.IsDispatcherBuilt() {
+		return nil, ErrDispatcherNotBuilt
+	}
+
 	ifaceIdx, err := GetInterfaceIndex(config.InterfaceName)
 	if err != nil {
 		return nil, err
 	}
 
+	// Loading BPF maps/programs requires a raised (or infinite) memlock
+	// rlimit on kernels without cgroup-based BPF memory accounting.
+	if err := SetRLimitMemlock(); err != nil {
+		return nil, fmt.Errorf("failed to raise memlock rlimit: %w", err)
+	}
+
 	xdp := &XDPProgram{
 		ifaceName: config.InterfaceName,
 		ifaceIdx:  ifaceIdx,
 		mode:      config.Mode,
 	}
 
-	// In a real implementation, you would:
-	// 1. Load the eBPF object file
-	// 2. Get the XDP program from the collection
-	// 3. Attach it to the interface
-	//
-	// Example with a real program:
-	// spec, err := ebpf.LoadCollectionSpec(config.ProgramPath)
-	// coll, err := ebpf.NewCollection(spec)
-	// xdp.prog = coll.Programs["xdp_prog"]
-	// xdp.link, err = link.AttachXDP(link.XDPOptions{...})
+	if err := bpf.LoadDispatcherObjects(&xdp.objs, nil); err != nil {
+		return nil, fmt.Errorf("failed to load eBPF dispatcher: %w", err)
+	}
+
+	xdp.link, err = link.AttachXDP(link.XDPOptions{
+		Program:   xdp.objs.XdpDispatcher,
+		Interface: ifaceIdx,
+		Flags:     xdpAttachFlags(config.Mode),
+	})
+	if err != nil {
+		xdp.objs.Close()
+		return nil, fmt.Errorf("failed to attach XDP program: %w", err)
+	}
 
 	return xdp, nil
 }
 
-// Detach removes the XDP program from the interface.
+// xdpAttachFlags maps our XDPMode to the link.XDPAttachFlags the kernel
+// expects: generic (SKB), native/driver, or hardware offload. XDPModeUnspec
+// maps to no flags, which leaves the choice of mode to the kernel.
+func xdpAttachFlags(mode XDPMode) link.XDPAttachFlags {
+	switch mode {
+	case XDPModeNative:
+		return link.XDPDriverMode
+	case XDPModeOffload:
+		return link.XDPOffloadMode
+	case XDPModeSKB:
+		return link.XDPGenericMode
+	default:
+		return 0
+	}
+}
+
+// Detach removes the XDP program from the interface and releases its maps.
 func (x *XDPProgram) Detach() error {
+	if err := x.objs.Close(); err != nil {
+		return err
+	}
 	if x.link != nil {
 		return x.link.Close()
 	}
 	return nil
 }
 
+// XSKMap returns the map an XDPSocket registers its fd into so the
+// dispatcher can redirect matching queues to it.
+func (x *XDPProgram) XSKMap() *ebpf.Map {
+	return x.objs.XsksMap
+}
+
+// DevMap returns the map used for interface-to-interface redirects.
+func (x *XDPProgram) DevMap() *ebpf.Map {
+	return x.objs.DevMap
+}
+
+// PacketCounters returns the per-CPU packet counter map.
+func (x *XDPProgram) PacketCounters() *ebpf.Map {
+	return x.objs.PacketCounters
+}
+
+// RedirectFlows returns the map of RX queue indices the dispatcher should
+// steer into xsks_map or cpu_map (see FanoutMode) instead of passing up
+// the normal network stack. xdp.SocketPool marks one entry per queue it
+// opens a socket for (or, in CPUMAP mode, per queue it redispatches).
+func (x *XDPProgram) RedirectFlows() *ebpf.Map {
+	return x.objs.RedirectFlows
+}
+
+// FanoutMode returns the single-entry map selecting how a redirected
+// queue is steered: per-queue AF_XDP (the zero value) or CPUMAP. See
+// FanoutPolicy and xdp.SocketPool.
+func (x *XDPProgram) FanoutMode() *ebpf.Map {
+	return x.objs.FanoutMode
+}
+
+// CPUMap returns the BPF_MAP_TYPE_CPUMAP the dispatcher redirects into
+// when FanoutMode is set to CPUMAP fan-out.
+func (x *XDPProgram) CPUMap() *ebpf.Map {
+	return x.objs.CpuMap
+}
+
+// QueueToCPU returns the map from RX queue index to target CPU id
+// consulted by the dispatcher's CPUMAP redirect path.
+func (x *XDPProgram) QueueToCPU() *ebpf.Map {
+	return x.objs.QueueToCpu
+}
+
+// RelayAllocations returns the map the dispatcher consults to rewrite and
+// XDP_TX UDP flows registered by offload.Relay.
+func (x *XDPProgram) RelayAllocations() *ebpf.Map {
+	return x.objs.RelayAllocations
+}
+
+// RelayCounters returns the per-allocation packet/byte counter map read by
+// offload.Relay.Stats.
+func (x *XDPProgram) RelayCounters() *ebpf.Map {
+	return x.objs.RelayCountersMap
+}
+
 // InterfaceName returns the interface name.
 func (x *XDPProgram) InterfaceName() string {
 	return x.ifaceName
@@ -176,12 +289,12 @@ func (a XDPAction) String() string {
 
 // GetXDPStats retrieves XDP statistics from the kernel.
 type XDPStats struct {
-	RxPackets  uint64
-	RxBytes    uint64
-	TxPackets  uint64
-	TxBytes    uint64
-	Drops      uint64
-	Errors     uint64
+	RxPackets uint64
+	RxBytes   uint64
+	TxPackets uint64
+	TxBytes   uint64
+	Drops     uint64
+	Errors    uint64
 }
 
 // GetInterfaceStats gets network interface statistics.